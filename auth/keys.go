@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+// TypeClaim names what a token is for. Every token the KeySet signs carries one and is
+// only accepted for that purpose, since one key signs tokens for several purposes.
+const TypeClaim = "typ"
+
+// AccessTokenType is the type of the access tokens that authenticate API requests.
+const AccessTokenType = "access"
+
+// KeySet signs JWTs with a single private key and verifies them with any of its
+// keys, picked by the "kid" header. Keeping the previous key for verification after
+// switching to a new signing key lets tokens it issued run out instead of failing.
+type KeySet struct {
+	signer    *jwtauth.JWTAuth
+	verifiers map[string]*jwtauth.JWTAuth
+	public    jwk.Set
+}
+
+// NewKeySet builds a KeySet from a private signing key and any number of older keys,
+// private or public, that tokens are still accepted from.
+func NewKeySet(signing jwk.Key, verification ...jwk.Key) (*KeySet, error) {
+	if signing == nil {
+		return nil, errors.New("no signing key configured")
+	}
+	ks := &KeySet{verifiers: map[string]*jwtauth.JWTAuth{}, public: jwk.NewSet()}
+
+	for i, key := range append([]jwk.Key{signing}, verification...) {
+		public, err := key.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		alg, err := keyAlgorithm(public)
+		if err != nil {
+			return nil, err
+		}
+		kid, err := keyID(public)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range []jwk.Key{key, public} {
+			k.Set(jwk.KeyIDKey, kid)
+			k.Set(jwk.AlgorithmKey, alg)
+		}
+		public.Set(jwk.KeyUsageKey, jwk.ForSignature)
+
+		if i == 0 {
+			if private, err := jwk.IsPrivateKey(key); err != nil || !private {
+				return nil, errors.New("the signing key must be a private key")
+			}
+			ks.signer = jwtauth.New(alg.String(), key, public)
+		}
+		if _, ok := ks.verifiers[kid]; ok {
+			continue
+		}
+		ks.verifiers[kid] = jwtauth.New(alg.String(), nil, public)
+		ks.public.AddKey(public)
+	}
+	return ks, nil
+}
+
+// LoadKeySet reads PEM encoded keys, such as ones made with
+// "openssl genpkey -algorithm ed25519" or "openssl genpkey -algorithm rsa".
+func LoadKeySet(signingFile string, verificationFiles ...string) (*KeySet, error) {
+	if signingFile == "" {
+		return nil, errors.New("no signing key configured")
+	}
+	var keys []jwk.Key
+	for _, file := range append([]string{signingFile}, verificationFiles...) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwk.ParseKey(data, jwk.WithPEM(true))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		keys = append(keys, key)
+	}
+	return NewKeySet(keys[0], keys[1:]...)
+}
+
+// keyAlgorithm picks the signing algorithm for a public key: RS256 for RSA, EdDSA for Ed25519.
+func keyAlgorithm(key jwk.Key) (jwa.SignatureAlgorithm, error) {
+	var raw interface{}
+	if err := key.Raw(&raw); err != nil {
+		return "", err
+	}
+	switch raw.(type) {
+	case *rsa.PublicKey:
+		return jwa.RS256, nil
+	case ed25519.PublicKey:
+		return jwa.EdDSA, nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T, use an RSA or Ed25519 key", raw)
+	}
+}
+
+// keyID derives the kid from the key's RFC 7638 thumbprint, so it stays the same
+// across restarts and on every instance sharing the key.
+func keyID(key jwk.Key) (string, error) {
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
+
+// Encode signs the claims as a token of the given type with the signing key. The
+// token's header names the key.
+func (ks *KeySet) Encode(tokenType string, claims map[string]interface{}) (jwt.Token, string, error) {
+	claims[TypeClaim] = tokenType
+	return ks.signer.Encode(claims)
+}
+
+// Verify checks the token's signature with the key named in its header, then its
+// expiry and other time based claims, and that it is of the given type.
+func (ks *KeySet) Verify(tokenString, tokenType string) (jwt.Token, error) {
+	msg, err := jws.Parse([]byte(tokenString))
+	if err != nil || len(msg.Signatures()) != 1 {
+		return nil, jwtauth.ErrUnauthorized
+	}
+	verifier, ok := ks.verifiers[msg.Signatures()[0].ProtectedHeaders().KeyID()]
+	if !ok {
+		return nil, jwtauth.ErrUnauthorized
+	}
+	token, err := jwtauth.VerifyToken(verifier, tokenString)
+	if err != nil {
+		return token, err
+	}
+	if typ, _ := token.PrivateClaims()[TypeClaim].(string); typ != tokenType {
+		return nil, jwtauth.ErrUnauthorized
+	}
+	return token, nil
+}
+
+// VerifyRequest verifies the access token in the Authorization header or, failing
+// that, the "jwt" cookie.
+func (ks *KeySet) VerifyRequest(r *http.Request) (jwt.Token, error) {
+	tokenString := jwtauth.TokenFromHeader(r)
+	if tokenString == "" {
+		tokenString = jwtauth.TokenFromCookie(r)
+	}
+	if tokenString == "" {
+		return nil, jwtauth.ErrNoTokenFound
+	}
+	return ks.Verify(tokenString, AccessTokenType)
+}
+
+// Verifier works like jwtauth.Verifier, for tokens signed by any key of the set.
+// It is followed by Authenticator, or a custom handler reading jwtauth.FromContext.
+func (ks *KeySet) Verifier() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := ks.VerifyRequest(r)
+			ctx := jwtauth.NewContext(r.Context(), token, err)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Authenticator rejects requests whose token Verifier didn't accept.
+func (ks *KeySet) Authenticator() func(http.Handler) http.Handler {
+	return jwtauth.Authenticator(ks.signer)
+}
+
+// JWKS returns the public keys of the set, for other services to verify tokens with.
+func (ks *KeySet) JWKS() jwk.Set {
+	return ks.public
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+func newTestKey(t *testing.T, raw interface{}) jwk.Key {
+	key, err := jwk.FromRaw(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// TestKeySetRotation tests that tokens signed with a rotated out key keep verifying
+// while it is listed, and that the JWKS only holds public keys
+func TestKeySetRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, unknownKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, err := NewKeySet(newTestKey(t, rsaKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := NewKeySet(newTestKey(t, edKey), newTestKey(t, rsaKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown, err := NewKeySet(newTestKey(t, unknownKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(ks *KeySet) string {
+		_, token, err := ks.Encode(AccessTokenType, map[string]interface{}{"userID": 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		verify  *KeySet
+		wantErr bool
+	}{
+		{"old key before rotation", sign(old), old, false},
+		{"old key after rotation", sign(old), rotated, false},
+		{"new key after rotation", sign(rotated), rotated, false},
+		{"new key on old set", sign(rotated), old, true},
+		{"unknown key", sign(unknown), rotated, true},
+		{"malformed token", "not-a-token", rotated, true},
+		{"token of another type", func() string {
+			_, token, err := rotated.Encode("mfa", map[string]interface{}{"userID": 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return token
+		}(), rotated, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.verify.Verify(tt.token, AccessTokenType); (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	jwks := rotated.JWKS()
+	if jwks.Len() != 2 {
+		t.Fatalf("JWKS has %d keys, want 2", jwks.Len())
+	}
+	for i := 0; i < jwks.Len(); i++ {
+		key, _ := jwks.Key(i)
+		if private, _ := jwk.IsPrivateKey(key); private {
+			t.Errorf("JWKS key %q is private", key.KeyID())
+		}
+		if key.KeyID() == "" {
+			t.Error("JWKS key has no kid")
+		}
+	}
+
+	if _, err := NewKeySet(nil); err == nil {
+		t.Error("NewKeySet(nil) succeeded, want an error")
+	}
+	public, _ := newTestKey(t, edKey).PublicKey()
+	if _, err := NewKeySet(public); err == nil {
+		t.Error("NewKeySet with a public signing key succeeded, want an error")
+	}
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password hashing algorithms.
+const (
+	Bcrypt   = "bcrypt"
+	Argon2id = "argon2id"
+)
+
+// bcryptMaxBytes is the length after which bcrypt ignores the rest of a password.
+const bcryptMaxBytes = 72
+
+// Argon2Params are the argon2id cost parameters, see RFC 9106.
+type Argon2Params struct {
+	// Memory in KiB
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+// PasswordHasher hashes passwords with the configured algorithm and cost, and checks
+// passwords against hashes made with any supported algorithm and cost.
+type PasswordHasher struct {
+	Algorithm  string
+	BcryptCost int
+	Argon2     Argon2Params
+
+	dummyOnce sync.Once
+	dummy     string
+}
+
+// DefaultPasswordHasher hashes with bcrypt at cost 12.
+var DefaultPasswordHasher, _ = NewPasswordHasher(Bcrypt)
+
+// NewPasswordHasher returns a hasher for the given algorithm with the recommended costs.
+func NewPasswordHasher(algorithm string) (*PasswordHasher, error) {
+	h := &PasswordHasher{
+		Algorithm:  algorithm,
+		BcryptCost: 12,
+		// The RFC 9106 second recommended option, for memory constrained servers
+		Argon2: Argon2Params{Memory: 64 * 1024, Time: 3, Threads: 4},
+	}
+	if algorithm != Bcrypt && algorithm != Argon2id {
+		return nil, fmt.Errorf("unknown password hashing algorithm %q", algorithm)
+	}
+	return h, nil
+}
+
+// Validate checks that the configured costs are usable.
+func (h *PasswordHasher) Validate() error {
+	if h.Algorithm == Bcrypt && (h.BcryptCost < bcrypt.MinCost || h.BcryptCost > bcrypt.MaxCost) {
+		return fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	if h.Algorithm == Argon2id && (h.Argon2.Memory == 0 || h.Argon2.Time == 0 || h.Argon2.Threads == 0) {
+		return errors.New("argon2id parameters must not be zero")
+	}
+	return nil
+}
+
+// Hash hashes the password for storage.
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.Algorithm == Argon2id {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		p := h.Argon2
+		key := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, 32)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.Memory, p.Time, p.Threads,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	}
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.BcryptCost)
+	return string(bytes), err
+}
+
+// Check reports whether the password matches the hash.
+func (h *PasswordHasher) Check(password, hash string) bool {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		p, salt, key, err := parseArgon2Hash(hash)
+		if err != nil {
+			return false
+		}
+		other := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// CheckDummy takes as long as checking a password against a real hash. It is used
+// when there is no user to check against, so timing doesn't tell whether one exists.
+func (h *PasswordHasher) CheckDummy(password string) {
+	h.dummyOnce.Do(func() {
+		h.dummy, _ = h.Hash("dummy password")
+	})
+	h.Check(password, h.dummy)
+}
+
+// NeedsRehash reports whether the hash was made with another algorithm or cost than
+// the configured one, so it should be replaced the next time the password is known.
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		p, _, _, err := parseArgon2Hash(hash)
+		return h.Algorithm != Argon2id || err != nil || p != h.Argon2
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return h.Algorithm != Bcrypt || err != nil || cost != h.BcryptCost
+}
+
+// parseArgon2Hash reads a hash in the PHC string format written by Hash.
+func parseArgon2Hash(hash string) (p Argon2Params, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != Argon2id {
+		return p, nil, nil, errors.New("malformed argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errors.New("unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return p, nil, nil, err
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, nil, nil, err
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return p, nil, nil, err
+	}
+	return p, salt, key, nil
+}
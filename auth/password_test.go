@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPasswordHasher tests hashing, checking and rehash detection across algorithms and costs
+func TestPasswordHasher(t *testing.T) {
+	bcryptLow := &PasswordHasher{Algorithm: Bcrypt, BcryptCost: 4}
+	bcryptHigh := &PasswordHasher{Algorithm: Bcrypt, BcryptCost: 5}
+	argon := &PasswordHasher{Algorithm: Argon2id, Argon2: Argon2Params{Memory: 1024, Time: 1, Threads: 1}}
+
+	tests := []struct {
+		name        string
+		hashWith    *PasswordHasher
+		checkWith   *PasswordHasher
+		needsRehash bool
+	}{
+		{"bcrypt, same cost", bcryptLow, bcryptLow, false},
+		{"bcrypt, cost raised", bcryptLow, bcryptHigh, true},
+		{"bcrypt to argon2id", bcryptLow, argon, true},
+		{"argon2id, same params", argon, argon, false},
+		{"argon2id to bcrypt", argon, bcryptLow, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.hashWith.Hash("correct horse")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.checkWith.Check("correct horse", hash) {
+				t.Error("Check() rejected the right password")
+			}
+			if tt.checkWith.Check("wrong horse", hash) {
+				t.Error("Check() accepted a wrong password")
+			}
+			if got := tt.checkWith.NeedsRehash(hash); got != tt.needsRehash {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.needsRehash)
+			}
+		})
+	}
+}
+
+// TestPasswordPolicy tests the length limits, the common password list and the breached password files
+func TestPasswordPolicy(t *testing.T) {
+	// SHA-1 of "breached-password"
+	dir := t.TempDir()
+	sum := "0E25372B435EE38A4C248D114B6A4DC6F0DA6FF1"
+	if err := os.WriteFile(filepath.Join(dir, sum[:5]+".txt"), []byte("0000000000000000000000000000000000A:1\r\n"+sum[5:]+":42\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy := &PasswordPolicy{MinLength: 8, MaxLength: 72, BreachedDir: dir}
+
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{"fine", "correct horse battery", false},
+		{"too short", "short", true},
+		{"multi-byte characters count once", "ééééééé", true},
+		{"too long", strings.Repeat("a", 73), true},
+		{"common", "Password123", true},
+		{"breached", "breached-password", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.password)
+			var policyErr *PolicyError
+			if tt.wantErr != errors.As(err, &policyErr) {
+				t.Errorf("Check(%q) = %v, wantErr %v", tt.password, err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := (&PasswordPolicy{MinLength: 8, MaxLength: 100}).Validate(&PasswordHasher{Algorithm: Bcrypt}); err == nil {
+		t.Error("Validate() allowed a maximum length bcrypt would truncate")
+	}
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList []byte
+
+// commonPasswords holds the bundled list of common passwords, lower-cased.
+var commonPasswords = func() map[string]struct{} {
+	passwords := map[string]struct{}{}
+	scanner := bufio.NewScanner(bytes.NewReader(commonPasswordList))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			passwords[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return passwords
+}()
+
+// PolicyError explains why a password was refused. Its message can be shown to the user.
+type PolicyError struct {
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return e.Reason
+}
+
+// PasswordPolicy decides which passwords users may choose.
+type PasswordPolicy struct {
+	// MinLength is counted in characters
+	MinLength int
+	// MaxLength is counted in bytes, at most 72 with bcrypt, which ignores the rest
+	MaxLength int
+	// BreachedDir optionally holds the Pwned Passwords list as k-anonymity range
+	// files, one "<first 5 SHA-1 hex digits>.txt" file of "SUFFIX:COUNT" lines per
+	// prefix, as written by the haveibeenpwned downloader.
+	BreachedDir string
+}
+
+// DefaultPasswordPolicy follows the NIST SP 800-63B guidance.
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 8, MaxLength: bcryptMaxBytes}
+
+// Validate checks that the policy makes sense for passwords hashed by h.
+func (p *PasswordPolicy) Validate(h *PasswordHasher) error {
+	if p.MinLength < 1 || p.MaxLength < p.MinLength {
+		return fmt.Errorf("password length limits %d-%d are invalid", p.MinLength, p.MaxLength)
+	}
+	if h.Algorithm == Bcrypt && p.MaxLength > bcryptMaxBytes {
+		return fmt.Errorf("bcrypt only uses the first %d bytes of a password, the maximum length can't exceed it", bcryptMaxBytes)
+	}
+	if p.BreachedDir != "" {
+		if info, err := os.Stat(p.BreachedDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("breached password directory %q is not a directory", p.BreachedDir)
+		}
+	}
+	return nil
+}
+
+// Check returns a *PolicyError if the password isn't allowed, or another error if the
+// breached password list couldn't be read.
+func (p *PasswordPolicy) Check(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return &PolicyError{fmt.Sprintf("password must be at least %d characters long", p.MinLength)}
+	}
+	if len(password) > p.MaxLength {
+		return &PolicyError{fmt.Sprintf("password must be at most %d bytes long", p.MaxLength)}
+	}
+	if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+		return &PolicyError{"password is too common, choose another one"}
+	}
+	if p.BreachedDir != "" {
+		breached, err := p.breached(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			return &PolicyError{"password has appeared in a data breach, choose another one"}
+		}
+	}
+	return nil
+}
+
+// breached looks the password's SHA-1 hash up in the range file for its prefix.
+func (p *PasswordPolicy) breached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	file, err := os.Open(filepath.Join(p.BreachedDir, prefix+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		candidate, _, _ := strings.Cut(scanner.Text(), ":")
+		if strings.EqualFold(strings.TrimSpace(candidate), suffix) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Permission names something a role allows beyond managing one's own data.
+type Permission string
+
+const (
+	// PermListUsers allows listing and searching all accounts
+	PermListUsers Permission = "users:list"
+	// PermManageUsers allows disabling and enabling accounts and forcing password resets
+	PermManageUsers Permission = "users:manage"
+	// PermReadAllTodos allows viewing any user's todos
+	PermReadAllTodos Permission = "todos:read_all"
+	// PermManageRoles allows assigning roles to other users
+	PermManageRoles Permission = "roles:manage"
+)
+
+// Permissions lists every permission a role can grant.
+var Permissions = []Permission{PermListUsers, PermManageUsers, PermReadAllTodos, PermManageRoles}
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Roles maps each role name to the permissions it grants. Roles it doesn't know
+// grant nothing.
+type Roles map[string][]Permission
+
+// DefaultRoles are the built-in roles: regular users and administrators.
+var DefaultRoles = Roles{
+	RoleUser:  nil,
+	RoleAdmin: Permissions,
+}
+
+// Allows reports whether the role grants the permission.
+func (roles Roles) Allows(role string, permission Permission) bool {
+	return slices.Contains(roles[role], permission)
+}
+
+// ParseRoles adds custom roles to the built-in ones. Roles are separated by ";" and
+// written as name=permission,permission, for example
+// "support=users:list,todos:read_all;moderator=users:list,users:manage".
+func ParseRoles(s string) (Roles, error) {
+	roles := Roles{}
+	for name, permissions := range DefaultRoles {
+		roles[name] = permissions
+	}
+	for _, definition := range strings.Split(s, ";") {
+		definition = strings.TrimSpace(definition)
+		if definition == "" {
+			continue
+		}
+		name, list, ok := strings.Cut(definition, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid role definition %q", definition)
+		}
+		if _, ok := DefaultRoles[name]; ok {
+			return nil, fmt.Errorf("built-in role %q can't be redefined", name)
+		}
+		var permissions []Permission
+		for _, p := range strings.Split(list, ",") {
+			permission := Permission(strings.TrimSpace(p))
+			if !slices.Contains(Permissions, permission) {
+				return nil, fmt.Errorf("role %q: unknown permission %q", name, p)
+			}
+			permissions = append(permissions, permission)
+		}
+		roles[name] = permissions
+	}
+	return roles, nil
+}
@@ -0,0 +1,43 @@
+package auth
+
+import "testing"
+
+// TestParseRoles tests custom role definitions and the permissions they grant
+func TestParseRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+		role    string
+		allowed []Permission
+		denied  []Permission
+	}{
+		{"no custom roles", "", false, RoleAdmin, Permissions, nil},
+		{"regular user", "", false, RoleUser, nil, Permissions},
+		{"custom role", "support=users:list, todos:read_all", false, "support", []Permission{PermListUsers, PermReadAllTodos}, []Permission{PermManageUsers}},
+		{"several roles", "support=users:list;moderator=users:manage", false, "moderator", []Permission{PermManageUsers}, []Permission{PermListUsers}},
+		{"unknown role", "support=users:list", false, "auditor", nil, Permissions},
+		{"unknown permission", "support=users:delete", true, "", nil, nil},
+		{"missing permissions", "support", true, "", nil, nil},
+		{"redefined built-in role", "admin=users:list", true, "", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles, err := ParseRoles(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRoles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, p := range tt.allowed {
+				if !roles.Allows(tt.role, p) {
+					t.Errorf("role %q should allow %s", tt.role, p)
+				}
+			}
+			for _, p := range tt.denied {
+				if roles.Allows(tt.role, p) {
+					t.Errorf("role %q should not allow %s", tt.role, p)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// TestValidateTOTP tests codes against the SHA-1 test vectors of RFC 6238, truncated to 6 digits
+func TestValidateTOTP(t *testing.T) {
+	// base32 of the ASCII key "12345678901234567890"
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	tests := []struct {
+		name string
+		unix int64
+		code string
+		want bool
+	}{
+		{"vector at 59", 59, "287082", true},
+		{"vector at 1111111109", 1111111109, "081804", true},
+		{"vector at 1234567890", 1234567890, "005924", true},
+		{"vector at 2000000000", 2000000000, "279037", true},
+		{"previous period allowed", 59 + 30, "287082", true},
+		{"two periods late rejected", 59 + 60, "287082", false},
+		{"wrong code", 59, "287083", false},
+		{"wrong length", 59, "87082", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := ValidateTOTP(secret, tt.code, time.Unix(tt.unix, 0)); got != tt.want {
+				t.Errorf("ValidateTOTP(%q, %d) = %v, want %v", tt.code, tt.unix, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,457 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo/ent/dataexport"
+	"todo/ent/predicate"
+	"todo/ent/user"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// DataExportUpdate is the builder for updating DataExport entities.
+type DataExportUpdate struct {
+	config
+	hooks    []Hook
+	mutation *DataExportMutation
+}
+
+// Where appends a list predicates to the DataExportUpdate builder.
+func (deu *DataExportUpdate) Where(ps ...predicate.DataExport) *DataExportUpdate {
+	deu.mutation.Where(ps...)
+	return deu
+}
+
+// SetStatus sets the "status" field.
+func (deu *DataExportUpdate) SetStatus(d dataexport.Status) *DataExportUpdate {
+	deu.mutation.SetStatus(d)
+	return deu
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (deu *DataExportUpdate) SetNillableStatus(d *dataexport.Status) *DataExportUpdate {
+	if d != nil {
+		deu.SetStatus(*d)
+	}
+	return deu
+}
+
+// SetArchive sets the "archive" field.
+func (deu *DataExportUpdate) SetArchive(b []byte) *DataExportUpdate {
+	deu.mutation.SetArchive(b)
+	return deu
+}
+
+// ClearArchive clears the value of the "archive" field.
+func (deu *DataExportUpdate) ClearArchive() *DataExportUpdate {
+	deu.mutation.ClearArchive()
+	return deu
+}
+
+// SetCompletedAt sets the "completed_at" field.
+func (deu *DataExportUpdate) SetCompletedAt(t time.Time) *DataExportUpdate {
+	deu.mutation.SetCompletedAt(t)
+	return deu
+}
+
+// SetNillableCompletedAt sets the "completed_at" field if the given value is not nil.
+func (deu *DataExportUpdate) SetNillableCompletedAt(t *time.Time) *DataExportUpdate {
+	if t != nil {
+		deu.SetCompletedAt(*t)
+	}
+	return deu
+}
+
+// ClearCompletedAt clears the value of the "completed_at" field.
+func (deu *DataExportUpdate) ClearCompletedAt() *DataExportUpdate {
+	deu.mutation.ClearCompletedAt()
+	return deu
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (deu *DataExportUpdate) SetExpiresAt(t time.Time) *DataExportUpdate {
+	deu.mutation.SetExpiresAt(t)
+	return deu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (deu *DataExportUpdate) SetNillableExpiresAt(t *time.Time) *DataExportUpdate {
+	if t != nil {
+		deu.SetExpiresAt(*t)
+	}
+	return deu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (deu *DataExportUpdate) SetUserID(id int) *DataExportUpdate {
+	deu.mutation.SetUserID(id)
+	return deu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (deu *DataExportUpdate) SetUser(u *User) *DataExportUpdate {
+	return deu.SetUserID(u.ID)
+}
+
+// Mutation returns the DataExportMutation object of the builder.
+func (deu *DataExportUpdate) Mutation() *DataExportMutation {
+	return deu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (deu *DataExportUpdate) ClearUser() *DataExportUpdate {
+	deu.mutation.ClearUser()
+	return deu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (deu *DataExportUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, deu.sqlSave, deu.mutation, deu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (deu *DataExportUpdate) SaveX(ctx context.Context) int {
+	affected, err := deu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (deu *DataExportUpdate) Exec(ctx context.Context) error {
+	_, err := deu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (deu *DataExportUpdate) ExecX(ctx context.Context) {
+	if err := deu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (deu *DataExportUpdate) check() error {
+	if v, ok := deu.mutation.Status(); ok {
+		if err := dataexport.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "DataExport.status": %w`, err)}
+		}
+	}
+	if _, ok := deu.mutation.UserID(); deu.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "DataExport.user"`)
+	}
+	return nil
+}
+
+func (deu *DataExportUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := deu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(dataexport.Table, dataexport.Columns, sqlgraph.NewFieldSpec(dataexport.FieldID, field.TypeInt))
+	if ps := deu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := deu.mutation.Status(); ok {
+		_spec.SetField(dataexport.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := deu.mutation.Archive(); ok {
+		_spec.SetField(dataexport.FieldArchive, field.TypeBytes, value)
+	}
+	if deu.mutation.ArchiveCleared() {
+		_spec.ClearField(dataexport.FieldArchive, field.TypeBytes)
+	}
+	if value, ok := deu.mutation.CompletedAt(); ok {
+		_spec.SetField(dataexport.FieldCompletedAt, field.TypeTime, value)
+	}
+	if deu.mutation.CompletedAtCleared() {
+		_spec.ClearField(dataexport.FieldCompletedAt, field.TypeTime)
+	}
+	if value, ok := deu.mutation.ExpiresAt(); ok {
+		_spec.SetField(dataexport.FieldExpiresAt, field.TypeTime, value)
+	}
+	if deu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   dataexport.UserTable,
+			Columns: []string{dataexport.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := deu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   dataexport.UserTable,
+			Columns: []string{dataexport.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, deu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{dataexport.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	deu.mutation.done = true
+	return n, nil
+}
+
+// DataExportUpdateOne is the builder for updating a single DataExport entity.
+type DataExportUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *DataExportMutation
+}
+
+// SetStatus sets the "status" field.
+func (deuo *DataExportUpdateOne) SetStatus(d dataexport.Status) *DataExportUpdateOne {
+	deuo.mutation.SetStatus(d)
+	return deuo
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (deuo *DataExportUpdateOne) SetNillableStatus(d *dataexport.Status) *DataExportUpdateOne {
+	if d != nil {
+		deuo.SetStatus(*d)
+	}
+	return deuo
+}
+
+// SetArchive sets the "archive" field.
+func (deuo *DataExportUpdateOne) SetArchive(b []byte) *DataExportUpdateOne {
+	deuo.mutation.SetArchive(b)
+	return deuo
+}
+
+// ClearArchive clears the value of the "archive" field.
+func (deuo *DataExportUpdateOne) ClearArchive() *DataExportUpdateOne {
+	deuo.mutation.ClearArchive()
+	return deuo
+}
+
+// SetCompletedAt sets the "completed_at" field.
+func (deuo *DataExportUpdateOne) SetCompletedAt(t time.Time) *DataExportUpdateOne {
+	deuo.mutation.SetCompletedAt(t)
+	return deuo
+}
+
+// SetNillableCompletedAt sets the "completed_at" field if the given value is not nil.
+func (deuo *DataExportUpdateOne) SetNillableCompletedAt(t *time.Time) *DataExportUpdateOne {
+	if t != nil {
+		deuo.SetCompletedAt(*t)
+	}
+	return deuo
+}
+
+// ClearCompletedAt clears the value of the "completed_at" field.
+func (deuo *DataExportUpdateOne) ClearCompletedAt() *DataExportUpdateOne {
+	deuo.mutation.ClearCompletedAt()
+	return deuo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (deuo *DataExportUpdateOne) SetExpiresAt(t time.Time) *DataExportUpdateOne {
+	deuo.mutation.SetExpiresAt(t)
+	return deuo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (deuo *DataExportUpdateOne) SetNillableExpiresAt(t *time.Time) *DataExportUpdateOne {
+	if t != nil {
+		deuo.SetExpiresAt(*t)
+	}
+	return deuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (deuo *DataExportUpdateOne) SetUserID(id int) *DataExportUpdateOne {
+	deuo.mutation.SetUserID(id)
+	return deuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (deuo *DataExportUpdateOne) SetUser(u *User) *DataExportUpdateOne {
+	return deuo.SetUserID(u.ID)
+}
+
+// Mutation returns the DataExportMutation object of the builder.
+func (deuo *DataExportUpdateOne) Mutation() *DataExportMutation {
+	return deuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (deuo *DataExportUpdateOne) ClearUser() *DataExportUpdateOne {
+	deuo.mutation.ClearUser()
+	return deuo
+}
+
+// Where appends a list predicates to the DataExportUpdate builder.
+func (deuo *DataExportUpdateOne) Where(ps ...predicate.DataExport) *DataExportUpdateOne {
+	deuo.mutation.Where(ps...)
+	return deuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (deuo *DataExportUpdateOne) Select(field string, fields ...string) *DataExportUpdateOne {
+	deuo.fields = append([]string{field}, fields...)
+	return deuo
+}
+
+// Save executes the query and returns the updated DataExport entity.
+func (deuo *DataExportUpdateOne) Save(ctx context.Context) (*DataExport, error) {
+	return withHooks(ctx, deuo.sqlSave, deuo.mutation, deuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (deuo *DataExportUpdateOne) SaveX(ctx context.Context) *DataExport {
+	node, err := deuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (deuo *DataExportUpdateOne) Exec(ctx context.Context) error {
+	_, err := deuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (deuo *DataExportUpdateOne) ExecX(ctx context.Context) {
+	if err := deuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (deuo *DataExportUpdateOne) check() error {
+	if v, ok := deuo.mutation.Status(); ok {
+		if err := dataexport.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "DataExport.status": %w`, err)}
+		}
+	}
+	if _, ok := deuo.mutation.UserID(); deuo.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "DataExport.user"`)
+	}
+	return nil
+}
+
+func (deuo *DataExportUpdateOne) sqlSave(ctx context.Context) (_node *DataExport, err error) {
+	if err := deuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(dataexport.Table, dataexport.Columns, sqlgraph.NewFieldSpec(dataexport.FieldID, field.TypeInt))
+	id, ok := deuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "DataExport.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := deuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, dataexport.FieldID)
+		for _, f := range fields {
+			if !dataexport.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != dataexport.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := deuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := deuo.mutation.Status(); ok {
+		_spec.SetField(dataexport.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := deuo.mutation.Archive(); ok {
+		_spec.SetField(dataexport.FieldArchive, field.TypeBytes, value)
+	}
+	if deuo.mutation.ArchiveCleared() {
+		_spec.ClearField(dataexport.FieldArchive, field.TypeBytes)
+	}
+	if value, ok := deuo.mutation.CompletedAt(); ok {
+		_spec.SetField(dataexport.FieldCompletedAt, field.TypeTime, value)
+	}
+	if deuo.mutation.CompletedAtCleared() {
+		_spec.ClearField(dataexport.FieldCompletedAt, field.TypeTime)
+	}
+	if value, ok := deuo.mutation.ExpiresAt(); ok {
+		_spec.SetField(dataexport.FieldExpiresAt, field.TypeTime, value)
+	}
+	if deuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   dataexport.UserTable,
+			Columns: []string{dataexport.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := deuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   dataexport.UserTable,
+			Columns: []string{dataexport.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &DataExport{config: deuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, deuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{dataexport.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	deuo.mutation.done = true
+	return _node, nil
+}
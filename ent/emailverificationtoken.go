@@ -0,0 +1,193 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"todo/ent/emailverificationtoken"
+	"todo/ent/user"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+// EmailVerificationToken is the model entity for the EmailVerificationToken schema.
+type EmailVerificationToken struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// TokenHash holds the value of the "token_hash" field.
+	TokenHash string `json:"-"`
+	// Email holds the value of the "email" field.
+	Email string `json:"email,omitempty"`
+	// ExpiresAt holds the value of the "expires_at" field.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UsedAt holds the value of the "used_at" field.
+	UsedAt *time.Time `json:"used_at,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the EmailVerificationTokenQuery when eager-loading is set.
+	Edges                          EmailVerificationTokenEdges `json:"edges"`
+	user_email_verification_tokens *int
+	selectValues                   sql.SelectValues
+}
+
+// EmailVerificationTokenEdges holds the relations/edges for other nodes in the graph.
+type EmailVerificationTokenEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e EmailVerificationTokenEdges) UserOrErr() (*User, error) {
+	if e.loadedTypes[0] {
+		if e.User == nil {
+			// Edge was loaded but was not found.
+			return nil, &NotFoundError{label: user.Label}
+		}
+		return e.User, nil
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*EmailVerificationToken) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case emailverificationtoken.FieldID:
+			values[i] = new(sql.NullInt64)
+		case emailverificationtoken.FieldTokenHash, emailverificationtoken.FieldEmail:
+			values[i] = new(sql.NullString)
+		case emailverificationtoken.FieldExpiresAt, emailverificationtoken.FieldCreatedAt, emailverificationtoken.FieldUsedAt:
+			values[i] = new(sql.NullTime)
+		case emailverificationtoken.ForeignKeys[0]: // user_email_verification_tokens
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the EmailVerificationToken fields.
+func (evt *EmailVerificationToken) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case emailverificationtoken.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			evt.ID = int(value.Int64)
+		case emailverificationtoken.FieldTokenHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field token_hash", values[i])
+			} else if value.Valid {
+				evt.TokenHash = value.String
+			}
+		case emailverificationtoken.FieldEmail:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field email", values[i])
+			} else if value.Valid {
+				evt.Email = value.String
+			}
+		case emailverificationtoken.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				evt.ExpiresAt = value.Time
+			}
+		case emailverificationtoken.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				evt.CreatedAt = value.Time
+			}
+		case emailverificationtoken.FieldUsedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field used_at", values[i])
+			} else if value.Valid {
+				evt.UsedAt = new(time.Time)
+				*evt.UsedAt = value.Time
+			}
+		case emailverificationtoken.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field user_email_verification_tokens", value)
+			} else if value.Valid {
+				evt.user_email_verification_tokens = new(int)
+				*evt.user_email_verification_tokens = int(value.Int64)
+			}
+		default:
+			evt.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the EmailVerificationToken.
+// This includes values selected through modifiers, order, etc.
+func (evt *EmailVerificationToken) Value(name string) (ent.Value, error) {
+	return evt.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the EmailVerificationToken entity.
+func (evt *EmailVerificationToken) QueryUser() *UserQuery {
+	return NewEmailVerificationTokenClient(evt.config).QueryUser(evt)
+}
+
+// Update returns a builder for updating this EmailVerificationToken.
+// Note that you need to call EmailVerificationToken.Unwrap() before calling this method if this EmailVerificationToken
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (evt *EmailVerificationToken) Update() *EmailVerificationTokenUpdateOne {
+	return NewEmailVerificationTokenClient(evt.config).UpdateOne(evt)
+}
+
+// Unwrap unwraps the EmailVerificationToken entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (evt *EmailVerificationToken) Unwrap() *EmailVerificationToken {
+	_tx, ok := evt.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: EmailVerificationToken is not a transactional entity")
+	}
+	evt.config.driver = _tx.drv
+	return evt
+}
+
+// String implements the fmt.Stringer.
+func (evt *EmailVerificationToken) String() string {
+	var builder strings.Builder
+	builder.WriteString("EmailVerificationToken(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", evt.ID))
+	builder.WriteString("token_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("email=")
+	builder.WriteString(evt.Email)
+	builder.WriteString(", ")
+	builder.WriteString("expires_at=")
+	builder.WriteString(evt.ExpiresAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(evt.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := evt.UsedAt; v != nil {
+		builder.WriteString("used_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// EmailVerificationTokens is a parsable slice of EmailVerificationToken.
+type EmailVerificationTokens []*EmailVerificationToken
@@ -0,0 +1,291 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo/ent/emailverificationtoken"
+	"todo/ent/user"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// EmailVerificationTokenCreate is the builder for creating a EmailVerificationToken entity.
+type EmailVerificationTokenCreate struct {
+	config
+	mutation *EmailVerificationTokenMutation
+	hooks    []Hook
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (evtc *EmailVerificationTokenCreate) SetTokenHash(s string) *EmailVerificationTokenCreate {
+	evtc.mutation.SetTokenHash(s)
+	return evtc
+}
+
+// SetEmail sets the "email" field.
+func (evtc *EmailVerificationTokenCreate) SetEmail(s string) *EmailVerificationTokenCreate {
+	evtc.mutation.SetEmail(s)
+	return evtc
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (evtc *EmailVerificationTokenCreate) SetExpiresAt(t time.Time) *EmailVerificationTokenCreate {
+	evtc.mutation.SetExpiresAt(t)
+	return evtc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (evtc *EmailVerificationTokenCreate) SetCreatedAt(t time.Time) *EmailVerificationTokenCreate {
+	evtc.mutation.SetCreatedAt(t)
+	return evtc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (evtc *EmailVerificationTokenCreate) SetNillableCreatedAt(t *time.Time) *EmailVerificationTokenCreate {
+	if t != nil {
+		evtc.SetCreatedAt(*t)
+	}
+	return evtc
+}
+
+// SetUsedAt sets the "used_at" field.
+func (evtc *EmailVerificationTokenCreate) SetUsedAt(t time.Time) *EmailVerificationTokenCreate {
+	evtc.mutation.SetUsedAt(t)
+	return evtc
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (evtc *EmailVerificationTokenCreate) SetNillableUsedAt(t *time.Time) *EmailVerificationTokenCreate {
+	if t != nil {
+		evtc.SetUsedAt(*t)
+	}
+	return evtc
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (evtc *EmailVerificationTokenCreate) SetUserID(id int) *EmailVerificationTokenCreate {
+	evtc.mutation.SetUserID(id)
+	return evtc
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (evtc *EmailVerificationTokenCreate) SetUser(u *User) *EmailVerificationTokenCreate {
+	return evtc.SetUserID(u.ID)
+}
+
+// Mutation returns the EmailVerificationTokenMutation object of the builder.
+func (evtc *EmailVerificationTokenCreate) Mutation() *EmailVerificationTokenMutation {
+	return evtc.mutation
+}
+
+// Save creates the EmailVerificationToken in the database.
+func (evtc *EmailVerificationTokenCreate) Save(ctx context.Context) (*EmailVerificationToken, error) {
+	evtc.defaults()
+	return withHooks(ctx, evtc.sqlSave, evtc.mutation, evtc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (evtc *EmailVerificationTokenCreate) SaveX(ctx context.Context) *EmailVerificationToken {
+	v, err := evtc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (evtc *EmailVerificationTokenCreate) Exec(ctx context.Context) error {
+	_, err := evtc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (evtc *EmailVerificationTokenCreate) ExecX(ctx context.Context) {
+	if err := evtc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (evtc *EmailVerificationTokenCreate) defaults() {
+	if _, ok := evtc.mutation.CreatedAt(); !ok {
+		v := emailverificationtoken.DefaultCreatedAt()
+		evtc.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (evtc *EmailVerificationTokenCreate) check() error {
+	if _, ok := evtc.mutation.TokenHash(); !ok {
+		return &ValidationError{Name: "token_hash", err: errors.New(`ent: missing required field "EmailVerificationToken.token_hash"`)}
+	}
+	if _, ok := evtc.mutation.Email(); !ok {
+		return &ValidationError{Name: "email", err: errors.New(`ent: missing required field "EmailVerificationToken.email"`)}
+	}
+	if _, ok := evtc.mutation.ExpiresAt(); !ok {
+		return &ValidationError{Name: "expires_at", err: errors.New(`ent: missing required field "EmailVerificationToken.expires_at"`)}
+	}
+	if _, ok := evtc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "EmailVerificationToken.created_at"`)}
+	}
+	if _, ok := evtc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "EmailVerificationToken.user"`)}
+	}
+	return nil
+}
+
+func (evtc *EmailVerificationTokenCreate) sqlSave(ctx context.Context) (*EmailVerificationToken, error) {
+	if err := evtc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := evtc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, evtc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	evtc.mutation.id = &_node.ID
+	evtc.mutation.done = true
+	return _node, nil
+}
+
+func (evtc *EmailVerificationTokenCreate) createSpec() (*EmailVerificationToken, *sqlgraph.CreateSpec) {
+	var (
+		_node = &EmailVerificationToken{config: evtc.config}
+		_spec = sqlgraph.NewCreateSpec(emailverificationtoken.Table, sqlgraph.NewFieldSpec(emailverificationtoken.FieldID, field.TypeInt))
+	)
+	if value, ok := evtc.mutation.TokenHash(); ok {
+		_spec.SetField(emailverificationtoken.FieldTokenHash, field.TypeString, value)
+		_node.TokenHash = value
+	}
+	if value, ok := evtc.mutation.Email(); ok {
+		_spec.SetField(emailverificationtoken.FieldEmail, field.TypeString, value)
+		_node.Email = value
+	}
+	if value, ok := evtc.mutation.ExpiresAt(); ok {
+		_spec.SetField(emailverificationtoken.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = value
+	}
+	if value, ok := evtc.mutation.CreatedAt(); ok {
+		_spec.SetField(emailverificationtoken.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := evtc.mutation.UsedAt(); ok {
+		_spec.SetField(emailverificationtoken.FieldUsedAt, field.TypeTime, value)
+		_node.UsedAt = &value
+	}
+	if nodes := evtc.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   emailverificationtoken.UserTable,
+			Columns: []string{emailverificationtoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.user_email_verification_tokens = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// EmailVerificationTokenCreateBulk is the builder for creating many EmailVerificationToken entities in bulk.
+type EmailVerificationTokenCreateBulk struct {
+	config
+	err      error
+	builders []*EmailVerificationTokenCreate
+}
+
+// Save creates the EmailVerificationToken entities in the database.
+func (evtcb *EmailVerificationTokenCreateBulk) Save(ctx context.Context) ([]*EmailVerificationToken, error) {
+	if evtcb.err != nil {
+		return nil, evtcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(evtcb.builders))
+	nodes := make([]*EmailVerificationToken, len(evtcb.builders))
+	mutators := make([]Mutator, len(evtcb.builders))
+	for i := range evtcb.builders {
+		func(i int, root context.Context) {
+			builder := evtcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*EmailVerificationTokenMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, evtcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, evtcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, evtcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (evtcb *EmailVerificationTokenCreateBulk) SaveX(ctx context.Context) []*EmailVerificationToken {
+	v, err := evtcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (evtcb *EmailVerificationTokenCreateBulk) Exec(ctx context.Context) error {
+	_, err := evtcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (evtcb *EmailVerificationTokenCreateBulk) ExecX(ctx context.Context) {
+	if err := evtcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
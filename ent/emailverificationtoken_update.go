@@ -0,0 +1,445 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo/ent/emailverificationtoken"
+	"todo/ent/predicate"
+	"todo/ent/user"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// EmailVerificationTokenUpdate is the builder for updating EmailVerificationToken entities.
+type EmailVerificationTokenUpdate struct {
+	config
+	hooks    []Hook
+	mutation *EmailVerificationTokenMutation
+}
+
+// Where appends a list predicates to the EmailVerificationTokenUpdate builder.
+func (evtu *EmailVerificationTokenUpdate) Where(ps ...predicate.EmailVerificationToken) *EmailVerificationTokenUpdate {
+	evtu.mutation.Where(ps...)
+	return evtu
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (evtu *EmailVerificationTokenUpdate) SetTokenHash(s string) *EmailVerificationTokenUpdate {
+	evtu.mutation.SetTokenHash(s)
+	return evtu
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (evtu *EmailVerificationTokenUpdate) SetNillableTokenHash(s *string) *EmailVerificationTokenUpdate {
+	if s != nil {
+		evtu.SetTokenHash(*s)
+	}
+	return evtu
+}
+
+// SetEmail sets the "email" field.
+func (evtu *EmailVerificationTokenUpdate) SetEmail(s string) *EmailVerificationTokenUpdate {
+	evtu.mutation.SetEmail(s)
+	return evtu
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (evtu *EmailVerificationTokenUpdate) SetNillableEmail(s *string) *EmailVerificationTokenUpdate {
+	if s != nil {
+		evtu.SetEmail(*s)
+	}
+	return evtu
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (evtu *EmailVerificationTokenUpdate) SetExpiresAt(t time.Time) *EmailVerificationTokenUpdate {
+	evtu.mutation.SetExpiresAt(t)
+	return evtu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (evtu *EmailVerificationTokenUpdate) SetNillableExpiresAt(t *time.Time) *EmailVerificationTokenUpdate {
+	if t != nil {
+		evtu.SetExpiresAt(*t)
+	}
+	return evtu
+}
+
+// SetUsedAt sets the "used_at" field.
+func (evtu *EmailVerificationTokenUpdate) SetUsedAt(t time.Time) *EmailVerificationTokenUpdate {
+	evtu.mutation.SetUsedAt(t)
+	return evtu
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (evtu *EmailVerificationTokenUpdate) SetNillableUsedAt(t *time.Time) *EmailVerificationTokenUpdate {
+	if t != nil {
+		evtu.SetUsedAt(*t)
+	}
+	return evtu
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (evtu *EmailVerificationTokenUpdate) ClearUsedAt() *EmailVerificationTokenUpdate {
+	evtu.mutation.ClearUsedAt()
+	return evtu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (evtu *EmailVerificationTokenUpdate) SetUserID(id int) *EmailVerificationTokenUpdate {
+	evtu.mutation.SetUserID(id)
+	return evtu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (evtu *EmailVerificationTokenUpdate) SetUser(u *User) *EmailVerificationTokenUpdate {
+	return evtu.SetUserID(u.ID)
+}
+
+// Mutation returns the EmailVerificationTokenMutation object of the builder.
+func (evtu *EmailVerificationTokenUpdate) Mutation() *EmailVerificationTokenMutation {
+	return evtu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (evtu *EmailVerificationTokenUpdate) ClearUser() *EmailVerificationTokenUpdate {
+	evtu.mutation.ClearUser()
+	return evtu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (evtu *EmailVerificationTokenUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, evtu.sqlSave, evtu.mutation, evtu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (evtu *EmailVerificationTokenUpdate) SaveX(ctx context.Context) int {
+	affected, err := evtu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (evtu *EmailVerificationTokenUpdate) Exec(ctx context.Context) error {
+	_, err := evtu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (evtu *EmailVerificationTokenUpdate) ExecX(ctx context.Context) {
+	if err := evtu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (evtu *EmailVerificationTokenUpdate) check() error {
+	if _, ok := evtu.mutation.UserID(); evtu.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "EmailVerificationToken.user"`)
+	}
+	return nil
+}
+
+func (evtu *EmailVerificationTokenUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := evtu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(emailverificationtoken.Table, emailverificationtoken.Columns, sqlgraph.NewFieldSpec(emailverificationtoken.FieldID, field.TypeInt))
+	if ps := evtu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := evtu.mutation.TokenHash(); ok {
+		_spec.SetField(emailverificationtoken.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := evtu.mutation.Email(); ok {
+		_spec.SetField(emailverificationtoken.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := evtu.mutation.ExpiresAt(); ok {
+		_spec.SetField(emailverificationtoken.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := evtu.mutation.UsedAt(); ok {
+		_spec.SetField(emailverificationtoken.FieldUsedAt, field.TypeTime, value)
+	}
+	if evtu.mutation.UsedAtCleared() {
+		_spec.ClearField(emailverificationtoken.FieldUsedAt, field.TypeTime)
+	}
+	if evtu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   emailverificationtoken.UserTable,
+			Columns: []string{emailverificationtoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := evtu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   emailverificationtoken.UserTable,
+			Columns: []string{emailverificationtoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, evtu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{emailverificationtoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	evtu.mutation.done = true
+	return n, nil
+}
+
+// EmailVerificationTokenUpdateOne is the builder for updating a single EmailVerificationToken entity.
+type EmailVerificationTokenUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *EmailVerificationTokenMutation
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (evtuo *EmailVerificationTokenUpdateOne) SetTokenHash(s string) *EmailVerificationTokenUpdateOne {
+	evtuo.mutation.SetTokenHash(s)
+	return evtuo
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (evtuo *EmailVerificationTokenUpdateOne) SetNillableTokenHash(s *string) *EmailVerificationTokenUpdateOne {
+	if s != nil {
+		evtuo.SetTokenHash(*s)
+	}
+	return evtuo
+}
+
+// SetEmail sets the "email" field.
+func (evtuo *EmailVerificationTokenUpdateOne) SetEmail(s string) *EmailVerificationTokenUpdateOne {
+	evtuo.mutation.SetEmail(s)
+	return evtuo
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (evtuo *EmailVerificationTokenUpdateOne) SetNillableEmail(s *string) *EmailVerificationTokenUpdateOne {
+	if s != nil {
+		evtuo.SetEmail(*s)
+	}
+	return evtuo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (evtuo *EmailVerificationTokenUpdateOne) SetExpiresAt(t time.Time) *EmailVerificationTokenUpdateOne {
+	evtuo.mutation.SetExpiresAt(t)
+	return evtuo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (evtuo *EmailVerificationTokenUpdateOne) SetNillableExpiresAt(t *time.Time) *EmailVerificationTokenUpdateOne {
+	if t != nil {
+		evtuo.SetExpiresAt(*t)
+	}
+	return evtuo
+}
+
+// SetUsedAt sets the "used_at" field.
+func (evtuo *EmailVerificationTokenUpdateOne) SetUsedAt(t time.Time) *EmailVerificationTokenUpdateOne {
+	evtuo.mutation.SetUsedAt(t)
+	return evtuo
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (evtuo *EmailVerificationTokenUpdateOne) SetNillableUsedAt(t *time.Time) *EmailVerificationTokenUpdateOne {
+	if t != nil {
+		evtuo.SetUsedAt(*t)
+	}
+	return evtuo
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (evtuo *EmailVerificationTokenUpdateOne) ClearUsedAt() *EmailVerificationTokenUpdateOne {
+	evtuo.mutation.ClearUsedAt()
+	return evtuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (evtuo *EmailVerificationTokenUpdateOne) SetUserID(id int) *EmailVerificationTokenUpdateOne {
+	evtuo.mutation.SetUserID(id)
+	return evtuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (evtuo *EmailVerificationTokenUpdateOne) SetUser(u *User) *EmailVerificationTokenUpdateOne {
+	return evtuo.SetUserID(u.ID)
+}
+
+// Mutation returns the EmailVerificationTokenMutation object of the builder.
+func (evtuo *EmailVerificationTokenUpdateOne) Mutation() *EmailVerificationTokenMutation {
+	return evtuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (evtuo *EmailVerificationTokenUpdateOne) ClearUser() *EmailVerificationTokenUpdateOne {
+	evtuo.mutation.ClearUser()
+	return evtuo
+}
+
+// Where appends a list predicates to the EmailVerificationTokenUpdate builder.
+func (evtuo *EmailVerificationTokenUpdateOne) Where(ps ...predicate.EmailVerificationToken) *EmailVerificationTokenUpdateOne {
+	evtuo.mutation.Where(ps...)
+	return evtuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (evtuo *EmailVerificationTokenUpdateOne) Select(field string, fields ...string) *EmailVerificationTokenUpdateOne {
+	evtuo.fields = append([]string{field}, fields...)
+	return evtuo
+}
+
+// Save executes the query and returns the updated EmailVerificationToken entity.
+func (evtuo *EmailVerificationTokenUpdateOne) Save(ctx context.Context) (*EmailVerificationToken, error) {
+	return withHooks(ctx, evtuo.sqlSave, evtuo.mutation, evtuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (evtuo *EmailVerificationTokenUpdateOne) SaveX(ctx context.Context) *EmailVerificationToken {
+	node, err := evtuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (evtuo *EmailVerificationTokenUpdateOne) Exec(ctx context.Context) error {
+	_, err := evtuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (evtuo *EmailVerificationTokenUpdateOne) ExecX(ctx context.Context) {
+	if err := evtuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (evtuo *EmailVerificationTokenUpdateOne) check() error {
+	if _, ok := evtuo.mutation.UserID(); evtuo.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "EmailVerificationToken.user"`)
+	}
+	return nil
+}
+
+func (evtuo *EmailVerificationTokenUpdateOne) sqlSave(ctx context.Context) (_node *EmailVerificationToken, err error) {
+	if err := evtuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(emailverificationtoken.Table, emailverificationtoken.Columns, sqlgraph.NewFieldSpec(emailverificationtoken.FieldID, field.TypeInt))
+	id, ok := evtuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "EmailVerificationToken.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := evtuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, emailverificationtoken.FieldID)
+		for _, f := range fields {
+			if !emailverificationtoken.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != emailverificationtoken.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := evtuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := evtuo.mutation.TokenHash(); ok {
+		_spec.SetField(emailverificationtoken.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := evtuo.mutation.Email(); ok {
+		_spec.SetField(emailverificationtoken.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := evtuo.mutation.ExpiresAt(); ok {
+		_spec.SetField(emailverificationtoken.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := evtuo.mutation.UsedAt(); ok {
+		_spec.SetField(emailverificationtoken.FieldUsedAt, field.TypeTime, value)
+	}
+	if evtuo.mutation.UsedAtCleared() {
+		_spec.ClearField(emailverificationtoken.FieldUsedAt, field.TypeTime)
+	}
+	if evtuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   emailverificationtoken.UserTable,
+			Columns: []string{emailverificationtoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := evtuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   emailverificationtoken.UserTable,
+			Columns: []string{emailverificationtoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &EmailVerificationToken{config: evtuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, evtuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{emailverificationtoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	evtuo.mutation.done = true
+	return _node, nil
+}
@@ -8,7 +8,20 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"todo/ent/accesstoken"
+	"todo/ent/dataexport"
+	"todo/ent/emailverificationtoken"
+	"todo/ent/identity"
+	"todo/ent/list"
+	"todo/ent/loginattempt"
+	"todo/ent/passwordresettoken"
+	"todo/ent/recoverycode"
+	"todo/ent/refreshtoken"
+	"todo/ent/revokedtoken"
+	"todo/ent/session"
+	"todo/ent/tag"
 	"todo/ent/todo"
+	"todo/ent/unlocktoken"
 	"todo/ent/user"
 
 	"entgo.io/ent"
@@ -74,8 +87,21 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			todo.Table: todo.ValidColumn,
-			user.Table: user.ValidColumn,
+			accesstoken.Table:            accesstoken.ValidColumn,
+			dataexport.Table:             dataexport.ValidColumn,
+			emailverificationtoken.Table: emailverificationtoken.ValidColumn,
+			identity.Table:               identity.ValidColumn,
+			list.Table:                   list.ValidColumn,
+			loginattempt.Table:           loginattempt.ValidColumn,
+			passwordresettoken.Table:     passwordresettoken.ValidColumn,
+			recoverycode.Table:           recoverycode.ValidColumn,
+			refreshtoken.Table:           refreshtoken.ValidColumn,
+			revokedtoken.Table:           revokedtoken.ValidColumn,
+			session.Table:                session.ValidColumn,
+			tag.Table:                    tag.ValidColumn,
+			todo.Table:                   todo.ValidColumn,
+			unlocktoken.Table:            unlocktoken.ValidColumn,
+			user.Table:                   user.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
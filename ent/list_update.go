@@ -0,0 +1,497 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todo/ent/list"
+	"todo/ent/predicate"
+	"todo/ent/todo"
+	"todo/ent/user"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// ListUpdate is the builder for updating List entities.
+type ListUpdate struct {
+	config
+	hooks    []Hook
+	mutation *ListMutation
+}
+
+// Where appends a list predicates to the ListUpdate builder.
+func (lu *ListUpdate) Where(ps ...predicate.List) *ListUpdate {
+	lu.mutation.Where(ps...)
+	return lu
+}
+
+// SetName sets the "name" field.
+func (lu *ListUpdate) SetName(s string) *ListUpdate {
+	lu.mutation.SetName(s)
+	return lu
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (lu *ListUpdate) SetNillableName(s *string) *ListUpdate {
+	if s != nil {
+		lu.SetName(*s)
+	}
+	return lu
+}
+
+// SetOwnerID sets the "owner" edge to the User entity by ID.
+func (lu *ListUpdate) SetOwnerID(id int) *ListUpdate {
+	lu.mutation.SetOwnerID(id)
+	return lu
+}
+
+// SetOwner sets the "owner" edge to the User entity.
+func (lu *ListUpdate) SetOwner(u *User) *ListUpdate {
+	return lu.SetOwnerID(u.ID)
+}
+
+// AddTodoIDs adds the "todos" edge to the Todo entity by IDs.
+func (lu *ListUpdate) AddTodoIDs(ids ...int) *ListUpdate {
+	lu.mutation.AddTodoIDs(ids...)
+	return lu
+}
+
+// AddTodos adds the "todos" edges to the Todo entity.
+func (lu *ListUpdate) AddTodos(t ...*Todo) *ListUpdate {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return lu.AddTodoIDs(ids...)
+}
+
+// Mutation returns the ListMutation object of the builder.
+func (lu *ListUpdate) Mutation() *ListMutation {
+	return lu.mutation
+}
+
+// ClearOwner clears the "owner" edge to the User entity.
+func (lu *ListUpdate) ClearOwner() *ListUpdate {
+	lu.mutation.ClearOwner()
+	return lu
+}
+
+// ClearTodos clears all "todos" edges to the Todo entity.
+func (lu *ListUpdate) ClearTodos() *ListUpdate {
+	lu.mutation.ClearTodos()
+	return lu
+}
+
+// RemoveTodoIDs removes the "todos" edge to Todo entities by IDs.
+func (lu *ListUpdate) RemoveTodoIDs(ids ...int) *ListUpdate {
+	lu.mutation.RemoveTodoIDs(ids...)
+	return lu
+}
+
+// RemoveTodos removes "todos" edges to Todo entities.
+func (lu *ListUpdate) RemoveTodos(t ...*Todo) *ListUpdate {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return lu.RemoveTodoIDs(ids...)
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (lu *ListUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, lu.sqlSave, lu.mutation, lu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (lu *ListUpdate) SaveX(ctx context.Context) int {
+	affected, err := lu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (lu *ListUpdate) Exec(ctx context.Context) error {
+	_, err := lu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lu *ListUpdate) ExecX(ctx context.Context) {
+	if err := lu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (lu *ListUpdate) check() error {
+	if v, ok := lu.mutation.Name(); ok {
+		if err := list.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "List.name": %w`, err)}
+		}
+	}
+	if _, ok := lu.mutation.OwnerID(); lu.mutation.OwnerCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "List.owner"`)
+	}
+	return nil
+}
+
+func (lu *ListUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := lu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(list.Table, list.Columns, sqlgraph.NewFieldSpec(list.FieldID, field.TypeInt))
+	if ps := lu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := lu.mutation.Name(); ok {
+		_spec.SetField(list.FieldName, field.TypeString, value)
+	}
+	if lu.mutation.OwnerCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   list.OwnerTable,
+			Columns: []string{list.OwnerColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := lu.mutation.OwnerIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   list.OwnerTable,
+			Columns: []string{list.OwnerColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if lu.mutation.TodosCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   list.TodosTable,
+			Columns: []string{list.TodosColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := lu.mutation.RemovedTodosIDs(); len(nodes) > 0 && !lu.mutation.TodosCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   list.TodosTable,
+			Columns: []string{list.TodosColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := lu.mutation.TodosIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   list.TodosTable,
+			Columns: []string{list.TodosColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, lu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{list.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	lu.mutation.done = true
+	return n, nil
+}
+
+// ListUpdateOne is the builder for updating a single List entity.
+type ListUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *ListMutation
+}
+
+// SetName sets the "name" field.
+func (luo *ListUpdateOne) SetName(s string) *ListUpdateOne {
+	luo.mutation.SetName(s)
+	return luo
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (luo *ListUpdateOne) SetNillableName(s *string) *ListUpdateOne {
+	if s != nil {
+		luo.SetName(*s)
+	}
+	return luo
+}
+
+// SetOwnerID sets the "owner" edge to the User entity by ID.
+func (luo *ListUpdateOne) SetOwnerID(id int) *ListUpdateOne {
+	luo.mutation.SetOwnerID(id)
+	return luo
+}
+
+// SetOwner sets the "owner" edge to the User entity.
+func (luo *ListUpdateOne) SetOwner(u *User) *ListUpdateOne {
+	return luo.SetOwnerID(u.ID)
+}
+
+// AddTodoIDs adds the "todos" edge to the Todo entity by IDs.
+func (luo *ListUpdateOne) AddTodoIDs(ids ...int) *ListUpdateOne {
+	luo.mutation.AddTodoIDs(ids...)
+	return luo
+}
+
+// AddTodos adds the "todos" edges to the Todo entity.
+func (luo *ListUpdateOne) AddTodos(t ...*Todo) *ListUpdateOne {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return luo.AddTodoIDs(ids...)
+}
+
+// Mutation returns the ListMutation object of the builder.
+func (luo *ListUpdateOne) Mutation() *ListMutation {
+	return luo.mutation
+}
+
+// ClearOwner clears the "owner" edge to the User entity.
+func (luo *ListUpdateOne) ClearOwner() *ListUpdateOne {
+	luo.mutation.ClearOwner()
+	return luo
+}
+
+// ClearTodos clears all "todos" edges to the Todo entity.
+func (luo *ListUpdateOne) ClearTodos() *ListUpdateOne {
+	luo.mutation.ClearTodos()
+	return luo
+}
+
+// RemoveTodoIDs removes the "todos" edge to Todo entities by IDs.
+func (luo *ListUpdateOne) RemoveTodoIDs(ids ...int) *ListUpdateOne {
+	luo.mutation.RemoveTodoIDs(ids...)
+	return luo
+}
+
+// RemoveTodos removes "todos" edges to Todo entities.
+func (luo *ListUpdateOne) RemoveTodos(t ...*Todo) *ListUpdateOne {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return luo.RemoveTodoIDs(ids...)
+}
+
+// Where appends a list predicates to the ListUpdate builder.
+func (luo *ListUpdateOne) Where(ps ...predicate.List) *ListUpdateOne {
+	luo.mutation.Where(ps...)
+	return luo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (luo *ListUpdateOne) Select(field string, fields ...string) *ListUpdateOne {
+	luo.fields = append([]string{field}, fields...)
+	return luo
+}
+
+// Save executes the query and returns the updated List entity.
+func (luo *ListUpdateOne) Save(ctx context.Context) (*List, error) {
+	return withHooks(ctx, luo.sqlSave, luo.mutation, luo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (luo *ListUpdateOne) SaveX(ctx context.Context) *List {
+	node, err := luo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (luo *ListUpdateOne) Exec(ctx context.Context) error {
+	_, err := luo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (luo *ListUpdateOne) ExecX(ctx context.Context) {
+	if err := luo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (luo *ListUpdateOne) check() error {
+	if v, ok := luo.mutation.Name(); ok {
+		if err := list.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "List.name": %w`, err)}
+		}
+	}
+	if _, ok := luo.mutation.OwnerID(); luo.mutation.OwnerCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "List.owner"`)
+	}
+	return nil
+}
+
+func (luo *ListUpdateOne) sqlSave(ctx context.Context) (_node *List, err error) {
+	if err := luo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(list.Table, list.Columns, sqlgraph.NewFieldSpec(list.FieldID, field.TypeInt))
+	id, ok := luo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "List.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := luo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, list.FieldID)
+		for _, f := range fields {
+			if !list.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != list.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := luo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := luo.mutation.Name(); ok {
+		_spec.SetField(list.FieldName, field.TypeString, value)
+	}
+	if luo.mutation.OwnerCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   list.OwnerTable,
+			Columns: []string{list.OwnerColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := luo.mutation.OwnerIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   list.OwnerTable,
+			Columns: []string{list.OwnerColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if luo.mutation.TodosCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   list.TodosTable,
+			Columns: []string{list.TodosColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := luo.mutation.RemovedTodosIDs(); len(nodes) > 0 && !luo.mutation.TodosCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   list.TodosTable,
+			Columns: []string{list.TodosColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := luo.mutation.TodosIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   list.TodosTable,
+			Columns: []string{list.TodosColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &List{config: luo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, luo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{list.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	luo.mutation.done = true
+	return _node, nil
+}
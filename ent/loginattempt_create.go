@@ -0,0 +1,301 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo/ent/loginattempt"
+	"todo/ent/user"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// LoginAttemptCreate is the builder for creating a LoginAttempt entity.
+type LoginAttemptCreate struct {
+	config
+	mutation *LoginAttemptMutation
+	hooks    []Hook
+}
+
+// SetEmail sets the "email" field.
+func (lac *LoginAttemptCreate) SetEmail(s string) *LoginAttemptCreate {
+	lac.mutation.SetEmail(s)
+	return lac
+}
+
+// SetSuccess sets the "success" field.
+func (lac *LoginAttemptCreate) SetSuccess(b bool) *LoginAttemptCreate {
+	lac.mutation.SetSuccess(b)
+	return lac
+}
+
+// SetIP sets the "ip" field.
+func (lac *LoginAttemptCreate) SetIP(s string) *LoginAttemptCreate {
+	lac.mutation.SetIP(s)
+	return lac
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (lac *LoginAttemptCreate) SetNillableIP(s *string) *LoginAttemptCreate {
+	if s != nil {
+		lac.SetIP(*s)
+	}
+	return lac
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (lac *LoginAttemptCreate) SetUserAgent(s string) *LoginAttemptCreate {
+	lac.mutation.SetUserAgent(s)
+	return lac
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (lac *LoginAttemptCreate) SetNillableUserAgent(s *string) *LoginAttemptCreate {
+	if s != nil {
+		lac.SetUserAgent(*s)
+	}
+	return lac
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (lac *LoginAttemptCreate) SetCreatedAt(t time.Time) *LoginAttemptCreate {
+	lac.mutation.SetCreatedAt(t)
+	return lac
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (lac *LoginAttemptCreate) SetNillableCreatedAt(t *time.Time) *LoginAttemptCreate {
+	if t != nil {
+		lac.SetCreatedAt(*t)
+	}
+	return lac
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (lac *LoginAttemptCreate) SetUserID(id int) *LoginAttemptCreate {
+	lac.mutation.SetUserID(id)
+	return lac
+}
+
+// SetNillableUserID sets the "user" edge to the User entity by ID if the given value is not nil.
+func (lac *LoginAttemptCreate) SetNillableUserID(id *int) *LoginAttemptCreate {
+	if id != nil {
+		lac = lac.SetUserID(*id)
+	}
+	return lac
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (lac *LoginAttemptCreate) SetUser(u *User) *LoginAttemptCreate {
+	return lac.SetUserID(u.ID)
+}
+
+// Mutation returns the LoginAttemptMutation object of the builder.
+func (lac *LoginAttemptCreate) Mutation() *LoginAttemptMutation {
+	return lac.mutation
+}
+
+// Save creates the LoginAttempt in the database.
+func (lac *LoginAttemptCreate) Save(ctx context.Context) (*LoginAttempt, error) {
+	lac.defaults()
+	return withHooks(ctx, lac.sqlSave, lac.mutation, lac.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (lac *LoginAttemptCreate) SaveX(ctx context.Context) *LoginAttempt {
+	v, err := lac.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (lac *LoginAttemptCreate) Exec(ctx context.Context) error {
+	_, err := lac.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lac *LoginAttemptCreate) ExecX(ctx context.Context) {
+	if err := lac.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (lac *LoginAttemptCreate) defaults() {
+	if _, ok := lac.mutation.CreatedAt(); !ok {
+		v := loginattempt.DefaultCreatedAt()
+		lac.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (lac *LoginAttemptCreate) check() error {
+	if _, ok := lac.mutation.Email(); !ok {
+		return &ValidationError{Name: "email", err: errors.New(`ent: missing required field "LoginAttempt.email"`)}
+	}
+	if _, ok := lac.mutation.Success(); !ok {
+		return &ValidationError{Name: "success", err: errors.New(`ent: missing required field "LoginAttempt.success"`)}
+	}
+	if _, ok := lac.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "LoginAttempt.created_at"`)}
+	}
+	return nil
+}
+
+func (lac *LoginAttemptCreate) sqlSave(ctx context.Context) (*LoginAttempt, error) {
+	if err := lac.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := lac.createSpec()
+	if err := sqlgraph.CreateNode(ctx, lac.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	lac.mutation.id = &_node.ID
+	lac.mutation.done = true
+	return _node, nil
+}
+
+func (lac *LoginAttemptCreate) createSpec() (*LoginAttempt, *sqlgraph.CreateSpec) {
+	var (
+		_node = &LoginAttempt{config: lac.config}
+		_spec = sqlgraph.NewCreateSpec(loginattempt.Table, sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt))
+	)
+	if value, ok := lac.mutation.Email(); ok {
+		_spec.SetField(loginattempt.FieldEmail, field.TypeString, value)
+		_node.Email = value
+	}
+	if value, ok := lac.mutation.Success(); ok {
+		_spec.SetField(loginattempt.FieldSuccess, field.TypeBool, value)
+		_node.Success = value
+	}
+	if value, ok := lac.mutation.IP(); ok {
+		_spec.SetField(loginattempt.FieldIP, field.TypeString, value)
+		_node.IP = value
+	}
+	if value, ok := lac.mutation.UserAgent(); ok {
+		_spec.SetField(loginattempt.FieldUserAgent, field.TypeString, value)
+		_node.UserAgent = value
+	}
+	if value, ok := lac.mutation.CreatedAt(); ok {
+		_spec.SetField(loginattempt.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if nodes := lac.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   loginattempt.UserTable,
+			Columns: []string{loginattempt.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.user_login_attempts = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// LoginAttemptCreateBulk is the builder for creating many LoginAttempt entities in bulk.
+type LoginAttemptCreateBulk struct {
+	config
+	err      error
+	builders []*LoginAttemptCreate
+}
+
+// Save creates the LoginAttempt entities in the database.
+func (lacb *LoginAttemptCreateBulk) Save(ctx context.Context) ([]*LoginAttempt, error) {
+	if lacb.err != nil {
+		return nil, lacb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(lacb.builders))
+	nodes := make([]*LoginAttempt, len(lacb.builders))
+	mutators := make([]Mutator, len(lacb.builders))
+	for i := range lacb.builders {
+		func(i int, root context.Context) {
+			builder := lacb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*LoginAttemptMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, lacb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, lacb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, lacb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (lacb *LoginAttemptCreateBulk) SaveX(ctx context.Context) []*LoginAttempt {
+	v, err := lacb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (lacb *LoginAttemptCreateBulk) Exec(ctx context.Context) error {
+	_, err := lacb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lacb *LoginAttemptCreateBulk) ExecX(ctx context.Context) {
+	if err := lacb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
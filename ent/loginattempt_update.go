@@ -0,0 +1,456 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todo/ent/loginattempt"
+	"todo/ent/predicate"
+	"todo/ent/user"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// LoginAttemptUpdate is the builder for updating LoginAttempt entities.
+type LoginAttemptUpdate struct {
+	config
+	hooks    []Hook
+	mutation *LoginAttemptMutation
+}
+
+// Where appends a list predicates to the LoginAttemptUpdate builder.
+func (lau *LoginAttemptUpdate) Where(ps ...predicate.LoginAttempt) *LoginAttemptUpdate {
+	lau.mutation.Where(ps...)
+	return lau
+}
+
+// SetEmail sets the "email" field.
+func (lau *LoginAttemptUpdate) SetEmail(s string) *LoginAttemptUpdate {
+	lau.mutation.SetEmail(s)
+	return lau
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (lau *LoginAttemptUpdate) SetNillableEmail(s *string) *LoginAttemptUpdate {
+	if s != nil {
+		lau.SetEmail(*s)
+	}
+	return lau
+}
+
+// SetSuccess sets the "success" field.
+func (lau *LoginAttemptUpdate) SetSuccess(b bool) *LoginAttemptUpdate {
+	lau.mutation.SetSuccess(b)
+	return lau
+}
+
+// SetNillableSuccess sets the "success" field if the given value is not nil.
+func (lau *LoginAttemptUpdate) SetNillableSuccess(b *bool) *LoginAttemptUpdate {
+	if b != nil {
+		lau.SetSuccess(*b)
+	}
+	return lau
+}
+
+// SetIP sets the "ip" field.
+func (lau *LoginAttemptUpdate) SetIP(s string) *LoginAttemptUpdate {
+	lau.mutation.SetIP(s)
+	return lau
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (lau *LoginAttemptUpdate) SetNillableIP(s *string) *LoginAttemptUpdate {
+	if s != nil {
+		lau.SetIP(*s)
+	}
+	return lau
+}
+
+// ClearIP clears the value of the "ip" field.
+func (lau *LoginAttemptUpdate) ClearIP() *LoginAttemptUpdate {
+	lau.mutation.ClearIP()
+	return lau
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (lau *LoginAttemptUpdate) SetUserAgent(s string) *LoginAttemptUpdate {
+	lau.mutation.SetUserAgent(s)
+	return lau
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (lau *LoginAttemptUpdate) SetNillableUserAgent(s *string) *LoginAttemptUpdate {
+	if s != nil {
+		lau.SetUserAgent(*s)
+	}
+	return lau
+}
+
+// ClearUserAgent clears the value of the "user_agent" field.
+func (lau *LoginAttemptUpdate) ClearUserAgent() *LoginAttemptUpdate {
+	lau.mutation.ClearUserAgent()
+	return lau
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (lau *LoginAttemptUpdate) SetUserID(id int) *LoginAttemptUpdate {
+	lau.mutation.SetUserID(id)
+	return lau
+}
+
+// SetNillableUserID sets the "user" edge to the User entity by ID if the given value is not nil.
+func (lau *LoginAttemptUpdate) SetNillableUserID(id *int) *LoginAttemptUpdate {
+	if id != nil {
+		lau = lau.SetUserID(*id)
+	}
+	return lau
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (lau *LoginAttemptUpdate) SetUser(u *User) *LoginAttemptUpdate {
+	return lau.SetUserID(u.ID)
+}
+
+// Mutation returns the LoginAttemptMutation object of the builder.
+func (lau *LoginAttemptUpdate) Mutation() *LoginAttemptMutation {
+	return lau.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (lau *LoginAttemptUpdate) ClearUser() *LoginAttemptUpdate {
+	lau.mutation.ClearUser()
+	return lau
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (lau *LoginAttemptUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, lau.sqlSave, lau.mutation, lau.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (lau *LoginAttemptUpdate) SaveX(ctx context.Context) int {
+	affected, err := lau.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (lau *LoginAttemptUpdate) Exec(ctx context.Context) error {
+	_, err := lau.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lau *LoginAttemptUpdate) ExecX(ctx context.Context) {
+	if err := lau.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (lau *LoginAttemptUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(loginattempt.Table, loginattempt.Columns, sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt))
+	if ps := lau.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := lau.mutation.Email(); ok {
+		_spec.SetField(loginattempt.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := lau.mutation.Success(); ok {
+		_spec.SetField(loginattempt.FieldSuccess, field.TypeBool, value)
+	}
+	if value, ok := lau.mutation.IP(); ok {
+		_spec.SetField(loginattempt.FieldIP, field.TypeString, value)
+	}
+	if lau.mutation.IPCleared() {
+		_spec.ClearField(loginattempt.FieldIP, field.TypeString)
+	}
+	if value, ok := lau.mutation.UserAgent(); ok {
+		_spec.SetField(loginattempt.FieldUserAgent, field.TypeString, value)
+	}
+	if lau.mutation.UserAgentCleared() {
+		_spec.ClearField(loginattempt.FieldUserAgent, field.TypeString)
+	}
+	if lau.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   loginattempt.UserTable,
+			Columns: []string{loginattempt.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := lau.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   loginattempt.UserTable,
+			Columns: []string{loginattempt.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, lau.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{loginattempt.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	lau.mutation.done = true
+	return n, nil
+}
+
+// LoginAttemptUpdateOne is the builder for updating a single LoginAttempt entity.
+type LoginAttemptUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *LoginAttemptMutation
+}
+
+// SetEmail sets the "email" field.
+func (lauo *LoginAttemptUpdateOne) SetEmail(s string) *LoginAttemptUpdateOne {
+	lauo.mutation.SetEmail(s)
+	return lauo
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (lauo *LoginAttemptUpdateOne) SetNillableEmail(s *string) *LoginAttemptUpdateOne {
+	if s != nil {
+		lauo.SetEmail(*s)
+	}
+	return lauo
+}
+
+// SetSuccess sets the "success" field.
+func (lauo *LoginAttemptUpdateOne) SetSuccess(b bool) *LoginAttemptUpdateOne {
+	lauo.mutation.SetSuccess(b)
+	return lauo
+}
+
+// SetNillableSuccess sets the "success" field if the given value is not nil.
+func (lauo *LoginAttemptUpdateOne) SetNillableSuccess(b *bool) *LoginAttemptUpdateOne {
+	if b != nil {
+		lauo.SetSuccess(*b)
+	}
+	return lauo
+}
+
+// SetIP sets the "ip" field.
+func (lauo *LoginAttemptUpdateOne) SetIP(s string) *LoginAttemptUpdateOne {
+	lauo.mutation.SetIP(s)
+	return lauo
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (lauo *LoginAttemptUpdateOne) SetNillableIP(s *string) *LoginAttemptUpdateOne {
+	if s != nil {
+		lauo.SetIP(*s)
+	}
+	return lauo
+}
+
+// ClearIP clears the value of the "ip" field.
+func (lauo *LoginAttemptUpdateOne) ClearIP() *LoginAttemptUpdateOne {
+	lauo.mutation.ClearIP()
+	return lauo
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (lauo *LoginAttemptUpdateOne) SetUserAgent(s string) *LoginAttemptUpdateOne {
+	lauo.mutation.SetUserAgent(s)
+	return lauo
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (lauo *LoginAttemptUpdateOne) SetNillableUserAgent(s *string) *LoginAttemptUpdateOne {
+	if s != nil {
+		lauo.SetUserAgent(*s)
+	}
+	return lauo
+}
+
+// ClearUserAgent clears the value of the "user_agent" field.
+func (lauo *LoginAttemptUpdateOne) ClearUserAgent() *LoginAttemptUpdateOne {
+	lauo.mutation.ClearUserAgent()
+	return lauo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (lauo *LoginAttemptUpdateOne) SetUserID(id int) *LoginAttemptUpdateOne {
+	lauo.mutation.SetUserID(id)
+	return lauo
+}
+
+// SetNillableUserID sets the "user" edge to the User entity by ID if the given value is not nil.
+func (lauo *LoginAttemptUpdateOne) SetNillableUserID(id *int) *LoginAttemptUpdateOne {
+	if id != nil {
+		lauo = lauo.SetUserID(*id)
+	}
+	return lauo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (lauo *LoginAttemptUpdateOne) SetUser(u *User) *LoginAttemptUpdateOne {
+	return lauo.SetUserID(u.ID)
+}
+
+// Mutation returns the LoginAttemptMutation object of the builder.
+func (lauo *LoginAttemptUpdateOne) Mutation() *LoginAttemptMutation {
+	return lauo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (lauo *LoginAttemptUpdateOne) ClearUser() *LoginAttemptUpdateOne {
+	lauo.mutation.ClearUser()
+	return lauo
+}
+
+// Where appends a list predicates to the LoginAttemptUpdate builder.
+func (lauo *LoginAttemptUpdateOne) Where(ps ...predicate.LoginAttempt) *LoginAttemptUpdateOne {
+	lauo.mutation.Where(ps...)
+	return lauo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (lauo *LoginAttemptUpdateOne) Select(field string, fields ...string) *LoginAttemptUpdateOne {
+	lauo.fields = append([]string{field}, fields...)
+	return lauo
+}
+
+// Save executes the query and returns the updated LoginAttempt entity.
+func (lauo *LoginAttemptUpdateOne) Save(ctx context.Context) (*LoginAttempt, error) {
+	return withHooks(ctx, lauo.sqlSave, lauo.mutation, lauo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (lauo *LoginAttemptUpdateOne) SaveX(ctx context.Context) *LoginAttempt {
+	node, err := lauo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (lauo *LoginAttemptUpdateOne) Exec(ctx context.Context) error {
+	_, err := lauo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lauo *LoginAttemptUpdateOne) ExecX(ctx context.Context) {
+	if err := lauo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (lauo *LoginAttemptUpdateOne) sqlSave(ctx context.Context) (_node *LoginAttempt, err error) {
+	_spec := sqlgraph.NewUpdateSpec(loginattempt.Table, loginattempt.Columns, sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt))
+	id, ok := lauo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "LoginAttempt.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := lauo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, loginattempt.FieldID)
+		for _, f := range fields {
+			if !loginattempt.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != loginattempt.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := lauo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := lauo.mutation.Email(); ok {
+		_spec.SetField(loginattempt.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := lauo.mutation.Success(); ok {
+		_spec.SetField(loginattempt.FieldSuccess, field.TypeBool, value)
+	}
+	if value, ok := lauo.mutation.IP(); ok {
+		_spec.SetField(loginattempt.FieldIP, field.TypeString, value)
+	}
+	if lauo.mutation.IPCleared() {
+		_spec.ClearField(loginattempt.FieldIP, field.TypeString)
+	}
+	if value, ok := lauo.mutation.UserAgent(); ok {
+		_spec.SetField(loginattempt.FieldUserAgent, field.TypeString, value)
+	}
+	if lauo.mutation.UserAgentCleared() {
+		_spec.ClearField(loginattempt.FieldUserAgent, field.TypeString)
+	}
+	if lauo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   loginattempt.UserTable,
+			Columns: []string{loginattempt.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := lauo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   loginattempt.UserTable,
+			Columns: []string{loginattempt.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &LoginAttempt{config: lauo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, lauo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{loginattempt.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	lauo.mutation.done = true
+	return _node, nil
+}
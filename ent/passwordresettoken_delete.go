@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"todo/ent/passwordresettoken"
+	"todo/ent/predicate"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// PasswordResetTokenDelete is the builder for deleting a PasswordResetToken entity.
+type PasswordResetTokenDelete struct {
+	config
+	hooks    []Hook
+	mutation *PasswordResetTokenMutation
+}
+
+// Where appends a list predicates to the PasswordResetTokenDelete builder.
+func (prtd *PasswordResetTokenDelete) Where(ps ...predicate.PasswordResetToken) *PasswordResetTokenDelete {
+	prtd.mutation.Where(ps...)
+	return prtd
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (prtd *PasswordResetTokenDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, prtd.sqlExec, prtd.mutation, prtd.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (prtd *PasswordResetTokenDelete) ExecX(ctx context.Context) int {
+	n, err := prtd.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (prtd *PasswordResetTokenDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(passwordresettoken.Table, sqlgraph.NewFieldSpec(passwordresettoken.FieldID, field.TypeInt))
+	if ps := prtd.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, prtd.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	prtd.mutation.done = true
+	return affected, err
+}
+
+// PasswordResetTokenDeleteOne is the builder for deleting a single PasswordResetToken entity.
+type PasswordResetTokenDeleteOne struct {
+	prtd *PasswordResetTokenDelete
+}
+
+// Where appends a list predicates to the PasswordResetTokenDelete builder.
+func (prtdo *PasswordResetTokenDeleteOne) Where(ps ...predicate.PasswordResetToken) *PasswordResetTokenDeleteOne {
+	prtdo.prtd.mutation.Where(ps...)
+	return prtdo
+}
+
+// Exec executes the deletion query.
+func (prtdo *PasswordResetTokenDeleteOne) Exec(ctx context.Context) error {
+	n, err := prtdo.prtd.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{passwordresettoken.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (prtdo *PasswordResetTokenDeleteOne) ExecX(ctx context.Context) {
+	if err := prtdo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -2,21 +2,4 @@
 
 package ent
 
-import (
-	"todo/ent/schema"
-	"todo/ent/user"
-)
-
-// The init function reads all schema descriptors with runtime code
-// (default values, validators, hooks and policies) and stitches it
-// to their package variables.
-func init() {
-	todoFields := schema.Todo{}.Fields()
-	_ = todoFields
-	userFields := schema.User{}.Fields()
-	_ = userFields
-	// userDescAge is the schema descriptor for age field.
-	userDescAge := userFields[0].Descriptor()
-	// user.AgeValidator is a validator for the "age" field. It is called by the builders before save.
-	user.AgeValidator = userDescAge.Validators[0].(func(int) error)
-}
+// The schema-stitching logic is generated in todo/ent/runtime/runtime.go
@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// AccessToken holds the schema definition for the AccessToken entity, a personal
+// access token scripts and CI use instead of logging in with a password.
+type AccessToken struct {
+	ent.Schema
+}
+
+// Fields of the AccessToken.
+func (AccessToken) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name").NotEmpty(),
+		// Only a SHA-256 hash of the token is stored, it is shown to the user once
+		field.String("token_hash").Unique().Sensitive(),
+		field.Strings("scopes"),
+		field.Time("expires_at"),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("last_used_at").Optional().Nillable(),
+		field.Time("revoked_at").Optional().Nillable(),
+	}
+}
+
+// Edges of the AccessToken.
+func (AccessToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("access_tokens").
+			Unique().
+			Required(),
+	}
+}
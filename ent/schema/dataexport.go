@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// DataExport holds the schema definition for the DataExport entity, an archive of
+// all of a user's personal data built in the background.
+type DataExport struct {
+	ent.Schema
+}
+
+// Fields of the DataExport.
+func (DataExport) Fields() []ent.Field {
+	return []ent.Field{
+		field.Enum("status").Values("pending", "ready", "failed").Default("pending"),
+		// The zip archive, set once the export is ready
+		field.Bytes("archive").Optional().Sensitive(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("completed_at").Optional().Nillable(),
+		field.Time("expires_at"),
+	}
+}
+
+// Edges of the DataExport.
+func (DataExport) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("data_exports").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the DataExport.
+func (DataExport) Indexes() []ent.Index {
+	return []ent.Index{
+		// One export at a time per user, even when two are requested at once
+		index.Edges("user").Unique().
+			Annotations(entsql.IndexWhere("status = 'pending'")),
+	}
+}
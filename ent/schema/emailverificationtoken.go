@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// EmailVerificationToken holds the schema definition for the EmailVerificationToken entity.
+type EmailVerificationToken struct {
+	ent.Schema
+}
+
+// Fields of the EmailVerificationToken.
+func (EmailVerificationToken) Fields() []ent.Field {
+	return []ent.Field{
+		// Only a SHA-256 hash of the token emailed to the user is stored
+		field.String("token_hash").Unique().Sensitive(),
+		// The address the token was sent to, it no longer verifies anything once the user changes email
+		field.String("email"),
+		field.Time("expires_at"),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("used_at").Optional().Nillable(),
+	}
+}
+
+// Edges of the EmailVerificationToken.
+func (EmailVerificationToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("email_verification_tokens").
+			Unique().
+			Required(),
+	}
+}
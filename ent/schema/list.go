@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// List holds the schema definition for the List entity.
+type List struct {
+	ent.Schema
+}
+
+// Fields of the List.
+func (List) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name").NotEmpty(),
+		field.Time("created_at").Default(time.Now).Immutable().
+			Annotations(entsql.DefaultExpr("CURRENT_TIMESTAMP")),
+	}
+}
+
+// Edges of the List.
+func (List) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("owner", User.Type).
+			Ref("lists").
+			Unique().
+			Required(),
+		// Todos are detached, not deleted, when their List is deleted
+		edge.To("todos", Todo.Type),
+	}
+}
+
+// Indexes of the List.
+func (List) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("name").Edges("owner").Unique(),
+	}
+}
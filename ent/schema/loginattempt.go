@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// LoginAttempt holds the schema definition for the LoginAttempt entity, a record of
+// every successful and failed login.
+type LoginAttempt struct {
+	ent.Schema
+}
+
+// Fields of the LoginAttempt.
+func (LoginAttempt) Fields() []ent.Field {
+	return []ent.Field{
+		// The email as entered, kept for attempts on addresses no user has
+		field.String("email"),
+		field.Bool("success"),
+		field.String("ip").Optional(),
+		field.String("user_agent").Optional(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+// Edges of the LoginAttempt.
+func (LoginAttempt) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("login_attempts").
+			Unique(),
+	}
+}
+
+// Indexes of the LoginAttempt.
+func (LoginAttempt) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("email", "created_at"),
+	}
+}
@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// PasswordResetToken holds the schema definition for the PasswordResetToken entity.
+type PasswordResetToken struct {
+	ent.Schema
+}
+
+// Fields of the PasswordResetToken.
+func (PasswordResetToken) Fields() []ent.Field {
+	return []ent.Field{
+		// Only a SHA-256 hash of the token emailed to the user is stored
+		field.String("token_hash").Unique().Sensitive(),
+		field.Time("expires_at"),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		// Set once the token has been used, tokens are single-use
+		field.Time("used_at").Optional().Nillable(),
+	}
+}
+
+// Edges of the PasswordResetToken.
+func (PasswordResetToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("password_reset_tokens").
+			Unique().
+			Required(),
+	}
+}
@@ -0,0 +1,41 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// RefreshToken holds the schema definition for the RefreshToken entity.
+type RefreshToken struct {
+	ent.Schema
+}
+
+// Fields of the RefreshToken.
+func (RefreshToken) Fields() []ent.Field {
+	return []ent.Field{
+		// Only a SHA-256 hash of the token handed to the client is stored
+		field.String("token_hash").Unique().Sensitive(),
+		field.Time("expires_at"),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("revoked_at").Optional().Nillable(),
+	}
+}
+
+// Edges of the RefreshToken.
+func (RefreshToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("refresh_tokens").
+			Unique().
+			Required(),
+		// Tokens rotated from the same login share a session, so reuse of an
+		// already rotated token can revoke the whole chain
+		edge.From("session", Session.Type).
+			Ref("refresh_tokens").
+			Unique().
+			Required(),
+	}
+}
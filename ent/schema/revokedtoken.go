@@ -0,0 +1,30 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// RevokedToken holds the schema definition for the RevokedToken entity, a
+// denylist of token IDs (jti) that must be rejected before they expire: revoked
+// access tokens and already used two-step login tokens.
+type RevokedToken struct {
+	ent.Schema
+}
+
+// Fields of the RevokedToken.
+func (RevokedToken) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("jti").Unique(),
+		// Entries can be removed once the token would have expired anyway
+		field.Time("expires_at"),
+	}
+}
+
+// Indexes of the RevokedToken.
+func (RevokedToken) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("expires_at"),
+	}
+}
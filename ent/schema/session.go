@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// Session holds the schema definition for the Session entity, one per login on a device.
+type Session struct {
+	ent.Schema
+}
+
+// Fields of the Session.
+func (Session) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("user_agent").Optional(),
+		field.String("ip").Optional(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("last_seen_at").Default(time.Now),
+		field.Time("revoked_at").Optional().Nillable(),
+	}
+}
+
+// Edges of the Session.
+func (Session) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("sessions").
+			Unique().
+			Required(),
+		// Every rotation of the session's refresh token adds a row
+		edge.To("refresh_tokens", RefreshToken.Type),
+	}
+}
@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"regexp"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Tag holds the schema definition for the Tag entity.
+type Tag struct {
+	ent.Schema
+}
+
+// Fields of the Tag.
+func (Tag) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name").NotEmpty(),
+		// Hex color used by clients to render the tag, e.g. "#ff8800"
+		field.String("color").Optional().Match(regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)),
+	}
+}
+
+// Edges of the Tag.
+func (Tag) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("owner", User.Type).
+			Ref("tags").
+			Unique().
+			Required(),
+		// A Tag can be attached to many todos and a Todo can have many tags
+		edge.To("todos", Todo.Type),
+	}
+}
+
+// Indexes of the Tag.
+func (Tag) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("name").Edges("owner").Unique(),
+	}
+}
@@ -1,7 +1,12 @@
 package schema
 
 import (
+	"time"
+	"todo/ent/privacy"
+	"todo/rule"
+
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
@@ -16,7 +21,29 @@ type Todo struct {
 func (Todo) Fields() []ent.Field {
 	return []ent.Field{
 		field.String("title"),
-		field.Enum("status").Values("incomplete", "complete").Default("incomplete"),
+		// Workflow state, validated against the configured workflow.Workflow
+		field.String("status").Default("todo"),
+		// Set when the Todo enters a terminal workflow state
+		field.Time("completed_at").Optional().Nillable(),
+		field.Enum("priority").Values("none", "low", "medium", "high", "urgent").Default("none"),
+		// Manual sort order chosen by the user, kept sparse so a move only rewrites one row
+		field.Int64("position").Default(0),
+		field.Time("start_at").Optional().Nillable(),
+		field.Time("due_at").Optional().Nillable(),
+		// The database default backfills rows that existed before the column was added
+		field.Time("created_at").Default(time.Now).Immutable().
+			Annotations(entsql.DefaultExpr("CURRENT_TIMESTAMP")),
+		field.Int("list_id").Optional().Nillable(),
+		field.Int("parent_id").Optional().Nillable(),
+		// Complete this Todo automatically once its last open subtask is completed
+		field.Bool("auto_complete").Default(false),
+		// RFC 5545 recurrence rule including its DTSTART, e.g. "DTSTART:20240301T090000Z\nRRULE:FREQ=WEEKLY;BYDAY=MO,WE"
+		field.String("recurrence").Optional().Nillable(),
+		// ID of the first Todo of a recurring series, shared by all of its occurrences
+		field.Int("series_id").Optional().Nillable(),
+		// Date the recurrence rule scheduled this occurrence for, which stays fixed
+		// when the due date of a single occurrence is moved
+		field.Time("occurrence_at").Optional().Nillable(),
 	}
 }
 
@@ -28,12 +55,49 @@ func (Todo) Edges() []ent.Edge {
 			Ref("todos"). // This should match the name of the edge defined in the User schema
 			Unique().     // Each Todo is linked to exactly one User
 			Required(),   // (Optional) if every Todo must be associated with a User
+		// A Todo may belong to at most one List
+		edge.From("list", List.Type).
+			Ref("todos").
+			Field("list_id").
+			Unique(),
+		edge.From("tags", Tag.Type).
+			Ref("todos"),
+		// Subtasks: a Todo has at most one parent and any number of children
+		edge.To("children", Todo.Type).
+			From("parent").
+			Field("parent_id").
+			Unique(),
 	}
 }
 
 // Indexes of the Todo.
 func (Todo) Indexes() []ent.Index {
 	return []ent.Index{
-		index.Fields("title").Unique(),
+		// Titles only need to be unique among an owner's open todos, so completed
+		// occurrences of a recurring Todo don't block the next one
+		index.Fields("title").Edges("user").Unique().
+			Annotations(entsql.IndexWhere("completed_at IS NULL")),
+	}
+}
+
+// Policy of the Todo, see package viewer.
+func (Todo) Policy() ent.Policy {
+	return privacy.Policy{
+		Query: privacy.QueryPolicy{
+			rule.DenyIfNoViewer(),
+			privacy.AlwaysAllowRule(),
+		},
+		Mutation: privacy.MutationPolicy{
+			rule.DenyIfNoViewer(),
+			rule.AllowIfSystem(),
+			rule.TodoOwnedByViewer(),
+		},
+	}
+}
+
+// Interceptors of the Todo.
+func (Todo) Interceptors() []ent.Interceptor {
+	return []ent.Interceptor{
+		rule.FilterTodos(),
 	}
 }
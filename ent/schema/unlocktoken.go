@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// UnlockToken holds the schema definition for the UnlockToken entity, emailed to a
+// user whose account was locked after too many failed logins.
+type UnlockToken struct {
+	ent.Schema
+}
+
+// Fields of the UnlockToken.
+func (UnlockToken) Fields() []ent.Field {
+	return []ent.Field{
+		// Only a SHA-256 hash of the token emailed to the user is stored
+		field.String("token_hash").Unique().Sensitive(),
+		field.Time("expires_at"),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("used_at").Optional().Nillable(),
+	}
+}
+
+// Edges of the UnlockToken.
+func (UnlockToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("unlock_tokens").
+			Unique().
+			Required(),
+	}
+}
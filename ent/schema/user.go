@@ -1,6 +1,9 @@
 package schema
 
 import (
+	"todo/ent/privacy"
+	"todo/rule"
+
 	"entgo.io/ent"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
@@ -15,10 +18,25 @@ type User struct {
 // Fields of the User.
 func (User) Fields() []ent.Field {
 	return []ent.Field{
-		field.Int("age").Positive(),
+		// Users signing in through an OpenID Connect provider don't give their age
+		field.Int("age").Positive().Optional(),
 		field.String("name"),
 		field.String("email").Unique(),
-		field.String("password"),
+		// Password hash, never serialized
+		field.String("password").Sensitive(),
+		field.Bool("email_verified").Default(false),
+		// Base32 TOTP secret, set at enrollment and only used once totp_enabled is confirmed
+		field.String("totp_secret").Optional().Nillable().Sensitive(),
+		field.Bool("totp_enabled").Default(false),
+		// Time step of the last accepted code, so a code can't be replayed
+		field.Int64("totp_last_step").Optional().Nillable(),
+		// Consecutive failed logins, reset by a successful one
+		field.Int("failed_login_count").Default(0),
+		field.Time("locked_until").Optional().Nillable(),
+		// Built-in "user" or "admin", or a custom role defined in the ROLES setting
+		field.String("role").Default("user"),
+		// Set while an administrator has disabled the account
+		field.Time("disabled_at").Optional().Nillable(),
 	}
 }
 
@@ -26,6 +44,18 @@ func (User) Fields() []ent.Field {
 func (User) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("todos", Todo.Type),
+		edge.To("lists", List.Type),
+		edge.To("tags", Tag.Type),
+		edge.To("refresh_tokens", RefreshToken.Type),
+		edge.To("sessions", Session.Type),
+		edge.To("password_reset_tokens", PasswordResetToken.Type),
+		edge.To("email_verification_tokens", EmailVerificationToken.Type),
+		edge.To("recovery_codes", RecoveryCode.Type),
+		edge.To("access_tokens", AccessToken.Type),
+		edge.To("identities", Identity.Type),
+		edge.To("login_attempts", LoginAttempt.Type),
+		edge.To("unlock_tokens", UnlockToken.Type),
+		edge.To("data_exports", DataExport.Type),
 	}
 }
 
@@ -36,3 +66,25 @@ func (User) Indexes() []ent.Index {
 		index.Fields("age", "name").Unique(),
 	}
 }
+
+// Policy of the User, see package viewer.
+func (User) Policy() ent.Policy {
+	return privacy.Policy{
+		Query: privacy.QueryPolicy{
+			rule.DenyIfNoViewer(),
+			privacy.AlwaysAllowRule(),
+		},
+		Mutation: privacy.MutationPolicy{
+			rule.DenyIfNoViewer(),
+			rule.AllowIfSystem(),
+			rule.UserIsViewer(),
+		},
+	}
+}
+
+// Interceptors of the User.
+func (User) Interceptors() []ent.Interceptor {
+	return []ent.Interceptor{
+		rule.FilterUsers(),
+	}
+}
@@ -5,6 +5,8 @@ package ent
 import (
 	"fmt"
 	"strings"
+	"time"
+	"todo/ent/list"
 	"todo/ent/todo"
 	"todo/ent/user"
 
@@ -20,7 +22,31 @@ type Todo struct {
 	// Title holds the value of the "title" field.
 	Title string `json:"title,omitempty"`
 	// Status holds the value of the "status" field.
-	Status todo.Status `json:"status,omitempty"`
+	Status string `json:"status,omitempty"`
+	// CompletedAt holds the value of the "completed_at" field.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Priority holds the value of the "priority" field.
+	Priority todo.Priority `json:"priority,omitempty"`
+	// Position holds the value of the "position" field.
+	Position int64 `json:"position,omitempty"`
+	// StartAt holds the value of the "start_at" field.
+	StartAt *time.Time `json:"start_at,omitempty"`
+	// DueAt holds the value of the "due_at" field.
+	DueAt *time.Time `json:"due_at,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// ListID holds the value of the "list_id" field.
+	ListID *int `json:"list_id,omitempty"`
+	// ParentID holds the value of the "parent_id" field.
+	ParentID *int `json:"parent_id,omitempty"`
+	// AutoComplete holds the value of the "auto_complete" field.
+	AutoComplete bool `json:"auto_complete,omitempty"`
+	// Recurrence holds the value of the "recurrence" field.
+	Recurrence *string `json:"recurrence,omitempty"`
+	// SeriesID holds the value of the "series_id" field.
+	SeriesID *int `json:"series_id,omitempty"`
+	// OccurrenceAt holds the value of the "occurrence_at" field.
+	OccurrenceAt *time.Time `json:"occurrence_at,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the TodoQuery when eager-loading is set.
 	Edges        TodoEdges `json:"edges"`
@@ -32,9 +58,17 @@ type Todo struct {
 type TodoEdges struct {
 	// User holds the value of the user edge.
 	User *User `json:"user,omitempty"`
+	// List holds the value of the list edge.
+	List *List `json:"list,omitempty"`
+	// Tags holds the value of the tags edge.
+	Tags []*Tag `json:"tags,omitempty"`
+	// Parent holds the value of the parent edge.
+	Parent *Todo `json:"parent,omitempty"`
+	// Children holds the value of the children edge.
+	Children []*Todo `json:"children,omitempty"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [1]bool
+	loadedTypes [5]bool
 }
 
 // UserOrErr returns the User value or an error if the edge
@@ -50,15 +84,63 @@ func (e TodoEdges) UserOrErr() (*User, error) {
 	return nil, &NotLoadedError{edge: "user"}
 }
 
+// ListOrErr returns the List value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e TodoEdges) ListOrErr() (*List, error) {
+	if e.loadedTypes[1] {
+		if e.List == nil {
+			// Edge was loaded but was not found.
+			return nil, &NotFoundError{label: list.Label}
+		}
+		return e.List, nil
+	}
+	return nil, &NotLoadedError{edge: "list"}
+}
+
+// TagsOrErr returns the Tags value or an error if the edge
+// was not loaded in eager-loading.
+func (e TodoEdges) TagsOrErr() ([]*Tag, error) {
+	if e.loadedTypes[2] {
+		return e.Tags, nil
+	}
+	return nil, &NotLoadedError{edge: "tags"}
+}
+
+// ParentOrErr returns the Parent value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e TodoEdges) ParentOrErr() (*Todo, error) {
+	if e.loadedTypes[3] {
+		if e.Parent == nil {
+			// Edge was loaded but was not found.
+			return nil, &NotFoundError{label: todo.Label}
+		}
+		return e.Parent, nil
+	}
+	return nil, &NotLoadedError{edge: "parent"}
+}
+
+// ChildrenOrErr returns the Children value or an error if the edge
+// was not loaded in eager-loading.
+func (e TodoEdges) ChildrenOrErr() ([]*Todo, error) {
+	if e.loadedTypes[4] {
+		return e.Children, nil
+	}
+	return nil, &NotLoadedError{edge: "children"}
+}
+
 // scanValues returns the types for scanning values from sql.Rows.
 func (*Todo) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case todo.FieldID:
+		case todo.FieldAutoComplete:
+			values[i] = new(sql.NullBool)
+		case todo.FieldID, todo.FieldPosition, todo.FieldListID, todo.FieldParentID, todo.FieldSeriesID:
 			values[i] = new(sql.NullInt64)
-		case todo.FieldTitle, todo.FieldStatus:
+		case todo.FieldTitle, todo.FieldStatus, todo.FieldPriority, todo.FieldRecurrence:
 			values[i] = new(sql.NullString)
+		case todo.FieldCompletedAt, todo.FieldStartAt, todo.FieldDueAt, todo.FieldCreatedAt, todo.FieldOccurrenceAt:
+			values[i] = new(sql.NullTime)
 		case todo.ForeignKeys[0]: // user_todos
 			values[i] = new(sql.NullInt64)
 		default:
@@ -92,7 +174,87 @@ func (t *Todo) assignValues(columns []string, values []any) error {
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field status", values[i])
 			} else if value.Valid {
-				t.Status = todo.Status(value.String)
+				t.Status = value.String
+			}
+		case todo.FieldCompletedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field completed_at", values[i])
+			} else if value.Valid {
+				t.CompletedAt = new(time.Time)
+				*t.CompletedAt = value.Time
+			}
+		case todo.FieldPriority:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field priority", values[i])
+			} else if value.Valid {
+				t.Priority = todo.Priority(value.String)
+			}
+		case todo.FieldPosition:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field position", values[i])
+			} else if value.Valid {
+				t.Position = value.Int64
+			}
+		case todo.FieldStartAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field start_at", values[i])
+			} else if value.Valid {
+				t.StartAt = new(time.Time)
+				*t.StartAt = value.Time
+			}
+		case todo.FieldDueAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field due_at", values[i])
+			} else if value.Valid {
+				t.DueAt = new(time.Time)
+				*t.DueAt = value.Time
+			}
+		case todo.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				t.CreatedAt = value.Time
+			}
+		case todo.FieldListID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field list_id", values[i])
+			} else if value.Valid {
+				t.ListID = new(int)
+				*t.ListID = int(value.Int64)
+			}
+		case todo.FieldParentID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field parent_id", values[i])
+			} else if value.Valid {
+				t.ParentID = new(int)
+				*t.ParentID = int(value.Int64)
+			}
+		case todo.FieldAutoComplete:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field auto_complete", values[i])
+			} else if value.Valid {
+				t.AutoComplete = value.Bool
+			}
+		case todo.FieldRecurrence:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field recurrence", values[i])
+			} else if value.Valid {
+				t.Recurrence = new(string)
+				*t.Recurrence = value.String
+			}
+		case todo.FieldSeriesID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field series_id", values[i])
+			} else if value.Valid {
+				t.SeriesID = new(int)
+				*t.SeriesID = int(value.Int64)
+			}
+		case todo.FieldOccurrenceAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field occurrence_at", values[i])
+			} else if value.Valid {
+				t.OccurrenceAt = new(time.Time)
+				*t.OccurrenceAt = value.Time
 			}
 		case todo.ForeignKeys[0]:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
@@ -119,6 +281,26 @@ func (t *Todo) QueryUser() *UserQuery {
 	return NewTodoClient(t.config).QueryUser(t)
 }
 
+// QueryList queries the "list" edge of the Todo entity.
+func (t *Todo) QueryList() *ListQuery {
+	return NewTodoClient(t.config).QueryList(t)
+}
+
+// QueryTags queries the "tags" edge of the Todo entity.
+func (t *Todo) QueryTags() *TagQuery {
+	return NewTodoClient(t.config).QueryTags(t)
+}
+
+// QueryParent queries the "parent" edge of the Todo entity.
+func (t *Todo) QueryParent() *TodoQuery {
+	return NewTodoClient(t.config).QueryParent(t)
+}
+
+// QueryChildren queries the "children" edge of the Todo entity.
+func (t *Todo) QueryChildren() *TodoQuery {
+	return NewTodoClient(t.config).QueryChildren(t)
+}
+
 // Update returns a builder for updating this Todo.
 // Note that you need to call Todo.Unwrap() before calling this method if this Todo
 // was returned from a transaction, and the transaction was committed or rolled back.
@@ -146,7 +328,59 @@ func (t *Todo) String() string {
 	builder.WriteString(t.Title)
 	builder.WriteString(", ")
 	builder.WriteString("status=")
-	builder.WriteString(fmt.Sprintf("%v", t.Status))
+	builder.WriteString(t.Status)
+	builder.WriteString(", ")
+	if v := t.CompletedAt; v != nil {
+		builder.WriteString("completed_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("priority=")
+	builder.WriteString(fmt.Sprintf("%v", t.Priority))
+	builder.WriteString(", ")
+	builder.WriteString("position=")
+	builder.WriteString(fmt.Sprintf("%v", t.Position))
+	builder.WriteString(", ")
+	if v := t.StartAt; v != nil {
+		builder.WriteString("start_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := t.DueAt; v != nil {
+		builder.WriteString("due_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(t.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := t.ListID; v != nil {
+		builder.WriteString("list_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := t.ParentID; v != nil {
+		builder.WriteString("parent_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("auto_complete=")
+	builder.WriteString(fmt.Sprintf("%v", t.AutoComplete))
+	builder.WriteString(", ")
+	if v := t.Recurrence; v != nil {
+		builder.WriteString("recurrence=")
+		builder.WriteString(*v)
+	}
+	builder.WriteString(", ")
+	if v := t.SeriesID; v != nil {
+		builder.WriteString("series_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := t.OccurrenceAt; v != nil {
+		builder.WriteString("occurrence_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -6,6 +6,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
+	"todo/ent/list"
+	"todo/ent/tag"
 	"todo/ent/todo"
 	"todo/ent/user"
 
@@ -27,15 +30,183 @@ func (tc *TodoCreate) SetTitle(s string) *TodoCreate {
 }
 
 // SetStatus sets the "status" field.
-func (tc *TodoCreate) SetStatus(t todo.Status) *TodoCreate {
-	tc.mutation.SetStatus(t)
+func (tc *TodoCreate) SetStatus(s string) *TodoCreate {
+	tc.mutation.SetStatus(s)
 	return tc
 }
 
 // SetNillableStatus sets the "status" field if the given value is not nil.
-func (tc *TodoCreate) SetNillableStatus(t *todo.Status) *TodoCreate {
+func (tc *TodoCreate) SetNillableStatus(s *string) *TodoCreate {
+	if s != nil {
+		tc.SetStatus(*s)
+	}
+	return tc
+}
+
+// SetCompletedAt sets the "completed_at" field.
+func (tc *TodoCreate) SetCompletedAt(t time.Time) *TodoCreate {
+	tc.mutation.SetCompletedAt(t)
+	return tc
+}
+
+// SetNillableCompletedAt sets the "completed_at" field if the given value is not nil.
+func (tc *TodoCreate) SetNillableCompletedAt(t *time.Time) *TodoCreate {
+	if t != nil {
+		tc.SetCompletedAt(*t)
+	}
+	return tc
+}
+
+// SetPriority sets the "priority" field.
+func (tc *TodoCreate) SetPriority(t todo.Priority) *TodoCreate {
+	tc.mutation.SetPriority(t)
+	return tc
+}
+
+// SetNillablePriority sets the "priority" field if the given value is not nil.
+func (tc *TodoCreate) SetNillablePriority(t *todo.Priority) *TodoCreate {
+	if t != nil {
+		tc.SetPriority(*t)
+	}
+	return tc
+}
+
+// SetPosition sets the "position" field.
+func (tc *TodoCreate) SetPosition(i int64) *TodoCreate {
+	tc.mutation.SetPosition(i)
+	return tc
+}
+
+// SetNillablePosition sets the "position" field if the given value is not nil.
+func (tc *TodoCreate) SetNillablePosition(i *int64) *TodoCreate {
+	if i != nil {
+		tc.SetPosition(*i)
+	}
+	return tc
+}
+
+// SetStartAt sets the "start_at" field.
+func (tc *TodoCreate) SetStartAt(t time.Time) *TodoCreate {
+	tc.mutation.SetStartAt(t)
+	return tc
+}
+
+// SetNillableStartAt sets the "start_at" field if the given value is not nil.
+func (tc *TodoCreate) SetNillableStartAt(t *time.Time) *TodoCreate {
+	if t != nil {
+		tc.SetStartAt(*t)
+	}
+	return tc
+}
+
+// SetDueAt sets the "due_at" field.
+func (tc *TodoCreate) SetDueAt(t time.Time) *TodoCreate {
+	tc.mutation.SetDueAt(t)
+	return tc
+}
+
+// SetNillableDueAt sets the "due_at" field if the given value is not nil.
+func (tc *TodoCreate) SetNillableDueAt(t *time.Time) *TodoCreate {
+	if t != nil {
+		tc.SetDueAt(*t)
+	}
+	return tc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (tc *TodoCreate) SetCreatedAt(t time.Time) *TodoCreate {
+	tc.mutation.SetCreatedAt(t)
+	return tc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (tc *TodoCreate) SetNillableCreatedAt(t *time.Time) *TodoCreate {
 	if t != nil {
-		tc.SetStatus(*t)
+		tc.SetCreatedAt(*t)
+	}
+	return tc
+}
+
+// SetListID sets the "list_id" field.
+func (tc *TodoCreate) SetListID(i int) *TodoCreate {
+	tc.mutation.SetListID(i)
+	return tc
+}
+
+// SetNillableListID sets the "list_id" field if the given value is not nil.
+func (tc *TodoCreate) SetNillableListID(i *int) *TodoCreate {
+	if i != nil {
+		tc.SetListID(*i)
+	}
+	return tc
+}
+
+// SetParentID sets the "parent_id" field.
+func (tc *TodoCreate) SetParentID(i int) *TodoCreate {
+	tc.mutation.SetParentID(i)
+	return tc
+}
+
+// SetNillableParentID sets the "parent_id" field if the given value is not nil.
+func (tc *TodoCreate) SetNillableParentID(i *int) *TodoCreate {
+	if i != nil {
+		tc.SetParentID(*i)
+	}
+	return tc
+}
+
+// SetAutoComplete sets the "auto_complete" field.
+func (tc *TodoCreate) SetAutoComplete(b bool) *TodoCreate {
+	tc.mutation.SetAutoComplete(b)
+	return tc
+}
+
+// SetNillableAutoComplete sets the "auto_complete" field if the given value is not nil.
+func (tc *TodoCreate) SetNillableAutoComplete(b *bool) *TodoCreate {
+	if b != nil {
+		tc.SetAutoComplete(*b)
+	}
+	return tc
+}
+
+// SetRecurrence sets the "recurrence" field.
+func (tc *TodoCreate) SetRecurrence(s string) *TodoCreate {
+	tc.mutation.SetRecurrence(s)
+	return tc
+}
+
+// SetNillableRecurrence sets the "recurrence" field if the given value is not nil.
+func (tc *TodoCreate) SetNillableRecurrence(s *string) *TodoCreate {
+	if s != nil {
+		tc.SetRecurrence(*s)
+	}
+	return tc
+}
+
+// SetSeriesID sets the "series_id" field.
+func (tc *TodoCreate) SetSeriesID(i int) *TodoCreate {
+	tc.mutation.SetSeriesID(i)
+	return tc
+}
+
+// SetNillableSeriesID sets the "series_id" field if the given value is not nil.
+func (tc *TodoCreate) SetNillableSeriesID(i *int) *TodoCreate {
+	if i != nil {
+		tc.SetSeriesID(*i)
+	}
+	return tc
+}
+
+// SetOccurrenceAt sets the "occurrence_at" field.
+func (tc *TodoCreate) SetOccurrenceAt(t time.Time) *TodoCreate {
+	tc.mutation.SetOccurrenceAt(t)
+	return tc
+}
+
+// SetNillableOccurrenceAt sets the "occurrence_at" field if the given value is not nil.
+func (tc *TodoCreate) SetNillableOccurrenceAt(t *time.Time) *TodoCreate {
+	if t != nil {
+		tc.SetOccurrenceAt(*t)
 	}
 	return tc
 }
@@ -51,6 +222,46 @@ func (tc *TodoCreate) SetUser(u *User) *TodoCreate {
 	return tc.SetUserID(u.ID)
 }
 
+// SetList sets the "list" edge to the List entity.
+func (tc *TodoCreate) SetList(l *List) *TodoCreate {
+	return tc.SetListID(l.ID)
+}
+
+// AddTagIDs adds the "tags" edge to the Tag entity by IDs.
+func (tc *TodoCreate) AddTagIDs(ids ...int) *TodoCreate {
+	tc.mutation.AddTagIDs(ids...)
+	return tc
+}
+
+// AddTags adds the "tags" edges to the Tag entity.
+func (tc *TodoCreate) AddTags(t ...*Tag) *TodoCreate {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tc.AddTagIDs(ids...)
+}
+
+// SetParent sets the "parent" edge to the Todo entity.
+func (tc *TodoCreate) SetParent(t *Todo) *TodoCreate {
+	return tc.SetParentID(t.ID)
+}
+
+// AddChildIDs adds the "children" edge to the Todo entity by IDs.
+func (tc *TodoCreate) AddChildIDs(ids ...int) *TodoCreate {
+	tc.mutation.AddChildIDs(ids...)
+	return tc
+}
+
+// AddChildren adds the "children" edges to the Todo entity.
+func (tc *TodoCreate) AddChildren(t ...*Todo) *TodoCreate {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tc.AddChildIDs(ids...)
+}
+
 // Mutation returns the TodoMutation object of the builder.
 func (tc *TodoCreate) Mutation() *TodoMutation {
 	return tc.mutation
@@ -58,7 +269,9 @@ func (tc *TodoCreate) Mutation() *TodoMutation {
 
 // Save creates the Todo in the database.
 func (tc *TodoCreate) Save(ctx context.Context) (*Todo, error) {
-	tc.defaults()
+	if err := tc.defaults(); err != nil {
+		return nil, err
+	}
 	return withHooks(ctx, tc.sqlSave, tc.mutation, tc.hooks)
 }
 
@@ -85,11 +298,31 @@ func (tc *TodoCreate) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (tc *TodoCreate) defaults() {
+func (tc *TodoCreate) defaults() error {
 	if _, ok := tc.mutation.Status(); !ok {
 		v := todo.DefaultStatus
 		tc.mutation.SetStatus(v)
 	}
+	if _, ok := tc.mutation.Priority(); !ok {
+		v := todo.DefaultPriority
+		tc.mutation.SetPriority(v)
+	}
+	if _, ok := tc.mutation.Position(); !ok {
+		v := todo.DefaultPosition
+		tc.mutation.SetPosition(v)
+	}
+	if _, ok := tc.mutation.CreatedAt(); !ok {
+		if todo.DefaultCreatedAt == nil {
+			return fmt.Errorf("ent: uninitialized todo.DefaultCreatedAt (forgotten import ent/runtime?)")
+		}
+		v := todo.DefaultCreatedAt()
+		tc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := tc.mutation.AutoComplete(); !ok {
+		v := todo.DefaultAutoComplete
+		tc.mutation.SetAutoComplete(v)
+	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -100,11 +333,20 @@ func (tc *TodoCreate) check() error {
 	if _, ok := tc.mutation.Status(); !ok {
 		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "Todo.status"`)}
 	}
-	if v, ok := tc.mutation.Status(); ok {
-		if err := todo.StatusValidator(v); err != nil {
-			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Todo.status": %w`, err)}
+	if _, ok := tc.mutation.Priority(); !ok {
+		return &ValidationError{Name: "priority", err: errors.New(`ent: missing required field "Todo.priority"`)}
+	}
+	if v, ok := tc.mutation.Priority(); ok {
+		if err := todo.PriorityValidator(v); err != nil {
+			return &ValidationError{Name: "priority", err: fmt.Errorf(`ent: validator failed for field "Todo.priority": %w`, err)}
 		}
 	}
+	if _, ok := tc.mutation.Position(); !ok {
+		return &ValidationError{Name: "position", err: errors.New(`ent: missing required field "Todo.position"`)}
+	}
+	if _, ok := tc.mutation.AutoComplete(); !ok {
+		return &ValidationError{Name: "auto_complete", err: errors.New(`ent: missing required field "Todo.auto_complete"`)}
+	}
 	if _, ok := tc.mutation.UserID(); !ok {
 		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "Todo.user"`)}
 	}
@@ -139,9 +381,49 @@ func (tc *TodoCreate) createSpec() (*Todo, *sqlgraph.CreateSpec) {
 		_node.Title = value
 	}
 	if value, ok := tc.mutation.Status(); ok {
-		_spec.SetField(todo.FieldStatus, field.TypeEnum, value)
+		_spec.SetField(todo.FieldStatus, field.TypeString, value)
 		_node.Status = value
 	}
+	if value, ok := tc.mutation.CompletedAt(); ok {
+		_spec.SetField(todo.FieldCompletedAt, field.TypeTime, value)
+		_node.CompletedAt = &value
+	}
+	if value, ok := tc.mutation.Priority(); ok {
+		_spec.SetField(todo.FieldPriority, field.TypeEnum, value)
+		_node.Priority = value
+	}
+	if value, ok := tc.mutation.Position(); ok {
+		_spec.SetField(todo.FieldPosition, field.TypeInt64, value)
+		_node.Position = value
+	}
+	if value, ok := tc.mutation.StartAt(); ok {
+		_spec.SetField(todo.FieldStartAt, field.TypeTime, value)
+		_node.StartAt = &value
+	}
+	if value, ok := tc.mutation.DueAt(); ok {
+		_spec.SetField(todo.FieldDueAt, field.TypeTime, value)
+		_node.DueAt = &value
+	}
+	if value, ok := tc.mutation.CreatedAt(); ok {
+		_spec.SetField(todo.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := tc.mutation.AutoComplete(); ok {
+		_spec.SetField(todo.FieldAutoComplete, field.TypeBool, value)
+		_node.AutoComplete = value
+	}
+	if value, ok := tc.mutation.Recurrence(); ok {
+		_spec.SetField(todo.FieldRecurrence, field.TypeString, value)
+		_node.Recurrence = &value
+	}
+	if value, ok := tc.mutation.SeriesID(); ok {
+		_spec.SetField(todo.FieldSeriesID, field.TypeInt, value)
+		_node.SeriesID = &value
+	}
+	if value, ok := tc.mutation.OccurrenceAt(); ok {
+		_spec.SetField(todo.FieldOccurrenceAt, field.TypeTime, value)
+		_node.OccurrenceAt = &value
+	}
 	if nodes := tc.mutation.UserIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -159,6 +441,72 @@ func (tc *TodoCreate) createSpec() (*Todo, *sqlgraph.CreateSpec) {
 		_node.user_todos = &nodes[0]
 		_spec.Edges = append(_spec.Edges, edge)
 	}
+	if nodes := tc.mutation.ListIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.ListTable,
+			Columns: []string{todo.ListColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(list.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.ListID = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := tc.mutation.TagsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   todo.TagsTable,
+			Columns: todo.TagsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tag.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := tc.mutation.ParentIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.ParentTable,
+			Columns: []string{todo.ParentColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.ParentID = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := tc.mutation.ChildrenIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   todo.ChildrenTable,
+			Columns: []string{todo.ChildrenColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	return _node, _spec
 }
 
@@ -6,7 +6,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
+	"todo/ent/list"
 	"todo/ent/predicate"
+	"todo/ent/tag"
 	"todo/ent/todo"
 	"todo/ent/user"
 
@@ -43,19 +46,235 @@ func (tu *TodoUpdate) SetNillableTitle(s *string) *TodoUpdate {
 }
 
 // SetStatus sets the "status" field.
-func (tu *TodoUpdate) SetStatus(t todo.Status) *TodoUpdate {
-	tu.mutation.SetStatus(t)
+func (tu *TodoUpdate) SetStatus(s string) *TodoUpdate {
+	tu.mutation.SetStatus(s)
 	return tu
 }
 
 // SetNillableStatus sets the "status" field if the given value is not nil.
-func (tu *TodoUpdate) SetNillableStatus(t *todo.Status) *TodoUpdate {
+func (tu *TodoUpdate) SetNillableStatus(s *string) *TodoUpdate {
+	if s != nil {
+		tu.SetStatus(*s)
+	}
+	return tu
+}
+
+// SetCompletedAt sets the "completed_at" field.
+func (tu *TodoUpdate) SetCompletedAt(t time.Time) *TodoUpdate {
+	tu.mutation.SetCompletedAt(t)
+	return tu
+}
+
+// SetNillableCompletedAt sets the "completed_at" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillableCompletedAt(t *time.Time) *TodoUpdate {
+	if t != nil {
+		tu.SetCompletedAt(*t)
+	}
+	return tu
+}
+
+// ClearCompletedAt clears the value of the "completed_at" field.
+func (tu *TodoUpdate) ClearCompletedAt() *TodoUpdate {
+	tu.mutation.ClearCompletedAt()
+	return tu
+}
+
+// SetPriority sets the "priority" field.
+func (tu *TodoUpdate) SetPriority(t todo.Priority) *TodoUpdate {
+	tu.mutation.SetPriority(t)
+	return tu
+}
+
+// SetNillablePriority sets the "priority" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillablePriority(t *todo.Priority) *TodoUpdate {
+	if t != nil {
+		tu.SetPriority(*t)
+	}
+	return tu
+}
+
+// SetPosition sets the "position" field.
+func (tu *TodoUpdate) SetPosition(i int64) *TodoUpdate {
+	tu.mutation.ResetPosition()
+	tu.mutation.SetPosition(i)
+	return tu
+}
+
+// SetNillablePosition sets the "position" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillablePosition(i *int64) *TodoUpdate {
+	if i != nil {
+		tu.SetPosition(*i)
+	}
+	return tu
+}
+
+// AddPosition adds i to the "position" field.
+func (tu *TodoUpdate) AddPosition(i int64) *TodoUpdate {
+	tu.mutation.AddPosition(i)
+	return tu
+}
+
+// SetStartAt sets the "start_at" field.
+func (tu *TodoUpdate) SetStartAt(t time.Time) *TodoUpdate {
+	tu.mutation.SetStartAt(t)
+	return tu
+}
+
+// SetNillableStartAt sets the "start_at" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillableStartAt(t *time.Time) *TodoUpdate {
 	if t != nil {
-		tu.SetStatus(*t)
+		tu.SetStartAt(*t)
+	}
+	return tu
+}
+
+// ClearStartAt clears the value of the "start_at" field.
+func (tu *TodoUpdate) ClearStartAt() *TodoUpdate {
+	tu.mutation.ClearStartAt()
+	return tu
+}
+
+// SetDueAt sets the "due_at" field.
+func (tu *TodoUpdate) SetDueAt(t time.Time) *TodoUpdate {
+	tu.mutation.SetDueAt(t)
+	return tu
+}
+
+// SetNillableDueAt sets the "due_at" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillableDueAt(t *time.Time) *TodoUpdate {
+	if t != nil {
+		tu.SetDueAt(*t)
+	}
+	return tu
+}
+
+// ClearDueAt clears the value of the "due_at" field.
+func (tu *TodoUpdate) ClearDueAt() *TodoUpdate {
+	tu.mutation.ClearDueAt()
+	return tu
+}
+
+// SetListID sets the "list_id" field.
+func (tu *TodoUpdate) SetListID(i int) *TodoUpdate {
+	tu.mutation.SetListID(i)
+	return tu
+}
+
+// SetNillableListID sets the "list_id" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillableListID(i *int) *TodoUpdate {
+	if i != nil {
+		tu.SetListID(*i)
 	}
 	return tu
 }
 
+// ClearListID clears the value of the "list_id" field.
+func (tu *TodoUpdate) ClearListID() *TodoUpdate {
+	tu.mutation.ClearListID()
+	return tu
+}
+
+// SetParentID sets the "parent_id" field.
+func (tu *TodoUpdate) SetParentID(i int) *TodoUpdate {
+	tu.mutation.SetParentID(i)
+	return tu
+}
+
+// SetNillableParentID sets the "parent_id" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillableParentID(i *int) *TodoUpdate {
+	if i != nil {
+		tu.SetParentID(*i)
+	}
+	return tu
+}
+
+// ClearParentID clears the value of the "parent_id" field.
+func (tu *TodoUpdate) ClearParentID() *TodoUpdate {
+	tu.mutation.ClearParentID()
+	return tu
+}
+
+// SetAutoComplete sets the "auto_complete" field.
+func (tu *TodoUpdate) SetAutoComplete(b bool) *TodoUpdate {
+	tu.mutation.SetAutoComplete(b)
+	return tu
+}
+
+// SetNillableAutoComplete sets the "auto_complete" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillableAutoComplete(b *bool) *TodoUpdate {
+	if b != nil {
+		tu.SetAutoComplete(*b)
+	}
+	return tu
+}
+
+// SetRecurrence sets the "recurrence" field.
+func (tu *TodoUpdate) SetRecurrence(s string) *TodoUpdate {
+	tu.mutation.SetRecurrence(s)
+	return tu
+}
+
+// SetNillableRecurrence sets the "recurrence" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillableRecurrence(s *string) *TodoUpdate {
+	if s != nil {
+		tu.SetRecurrence(*s)
+	}
+	return tu
+}
+
+// ClearRecurrence clears the value of the "recurrence" field.
+func (tu *TodoUpdate) ClearRecurrence() *TodoUpdate {
+	tu.mutation.ClearRecurrence()
+	return tu
+}
+
+// SetSeriesID sets the "series_id" field.
+func (tu *TodoUpdate) SetSeriesID(i int) *TodoUpdate {
+	tu.mutation.ResetSeriesID()
+	tu.mutation.SetSeriesID(i)
+	return tu
+}
+
+// SetNillableSeriesID sets the "series_id" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillableSeriesID(i *int) *TodoUpdate {
+	if i != nil {
+		tu.SetSeriesID(*i)
+	}
+	return tu
+}
+
+// AddSeriesID adds i to the "series_id" field.
+func (tu *TodoUpdate) AddSeriesID(i int) *TodoUpdate {
+	tu.mutation.AddSeriesID(i)
+	return tu
+}
+
+// ClearSeriesID clears the value of the "series_id" field.
+func (tu *TodoUpdate) ClearSeriesID() *TodoUpdate {
+	tu.mutation.ClearSeriesID()
+	return tu
+}
+
+// SetOccurrenceAt sets the "occurrence_at" field.
+func (tu *TodoUpdate) SetOccurrenceAt(t time.Time) *TodoUpdate {
+	tu.mutation.SetOccurrenceAt(t)
+	return tu
+}
+
+// SetNillableOccurrenceAt sets the "occurrence_at" field if the given value is not nil.
+func (tu *TodoUpdate) SetNillableOccurrenceAt(t *time.Time) *TodoUpdate {
+	if t != nil {
+		tu.SetOccurrenceAt(*t)
+	}
+	return tu
+}
+
+// ClearOccurrenceAt clears the value of the "occurrence_at" field.
+func (tu *TodoUpdate) ClearOccurrenceAt() *TodoUpdate {
+	tu.mutation.ClearOccurrenceAt()
+	return tu
+}
+
 // SetUserID sets the "user" edge to the User entity by ID.
 func (tu *TodoUpdate) SetUserID(id int) *TodoUpdate {
 	tu.mutation.SetUserID(id)
@@ -67,6 +286,46 @@ func (tu *TodoUpdate) SetUser(u *User) *TodoUpdate {
 	return tu.SetUserID(u.ID)
 }
 
+// SetList sets the "list" edge to the List entity.
+func (tu *TodoUpdate) SetList(l *List) *TodoUpdate {
+	return tu.SetListID(l.ID)
+}
+
+// AddTagIDs adds the "tags" edge to the Tag entity by IDs.
+func (tu *TodoUpdate) AddTagIDs(ids ...int) *TodoUpdate {
+	tu.mutation.AddTagIDs(ids...)
+	return tu
+}
+
+// AddTags adds the "tags" edges to the Tag entity.
+func (tu *TodoUpdate) AddTags(t ...*Tag) *TodoUpdate {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tu.AddTagIDs(ids...)
+}
+
+// SetParent sets the "parent" edge to the Todo entity.
+func (tu *TodoUpdate) SetParent(t *Todo) *TodoUpdate {
+	return tu.SetParentID(t.ID)
+}
+
+// AddChildIDs adds the "children" edge to the Todo entity by IDs.
+func (tu *TodoUpdate) AddChildIDs(ids ...int) *TodoUpdate {
+	tu.mutation.AddChildIDs(ids...)
+	return tu
+}
+
+// AddChildren adds the "children" edges to the Todo entity.
+func (tu *TodoUpdate) AddChildren(t ...*Todo) *TodoUpdate {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tu.AddChildIDs(ids...)
+}
+
 // Mutation returns the TodoMutation object of the builder.
 func (tu *TodoUpdate) Mutation() *TodoMutation {
 	return tu.mutation
@@ -78,6 +337,60 @@ func (tu *TodoUpdate) ClearUser() *TodoUpdate {
 	return tu
 }
 
+// ClearList clears the "list" edge to the List entity.
+func (tu *TodoUpdate) ClearList() *TodoUpdate {
+	tu.mutation.ClearList()
+	return tu
+}
+
+// ClearTags clears all "tags" edges to the Tag entity.
+func (tu *TodoUpdate) ClearTags() *TodoUpdate {
+	tu.mutation.ClearTags()
+	return tu
+}
+
+// RemoveTagIDs removes the "tags" edge to Tag entities by IDs.
+func (tu *TodoUpdate) RemoveTagIDs(ids ...int) *TodoUpdate {
+	tu.mutation.RemoveTagIDs(ids...)
+	return tu
+}
+
+// RemoveTags removes "tags" edges to Tag entities.
+func (tu *TodoUpdate) RemoveTags(t ...*Tag) *TodoUpdate {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tu.RemoveTagIDs(ids...)
+}
+
+// ClearParent clears the "parent" edge to the Todo entity.
+func (tu *TodoUpdate) ClearParent() *TodoUpdate {
+	tu.mutation.ClearParent()
+	return tu
+}
+
+// ClearChildren clears all "children" edges to the Todo entity.
+func (tu *TodoUpdate) ClearChildren() *TodoUpdate {
+	tu.mutation.ClearChildren()
+	return tu
+}
+
+// RemoveChildIDs removes the "children" edge to Todo entities by IDs.
+func (tu *TodoUpdate) RemoveChildIDs(ids ...int) *TodoUpdate {
+	tu.mutation.RemoveChildIDs(ids...)
+	return tu
+}
+
+// RemoveChildren removes "children" edges to Todo entities.
+func (tu *TodoUpdate) RemoveChildren(t ...*Todo) *TodoUpdate {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tu.RemoveChildIDs(ids...)
+}
+
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (tu *TodoUpdate) Save(ctx context.Context) (int, error) {
 	return withHooks(ctx, tu.sqlSave, tu.mutation, tu.hooks)
@@ -89,130 +402,545 @@ func (tu *TodoUpdate) SaveX(ctx context.Context) int {
 	if err != nil {
 		panic(err)
 	}
-	return affected
+	return affected
+}
+
+// Exec executes the query.
+func (tu *TodoUpdate) Exec(ctx context.Context) error {
+	_, err := tu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (tu *TodoUpdate) ExecX(ctx context.Context) {
+	if err := tu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (tu *TodoUpdate) check() error {
+	if v, ok := tu.mutation.Priority(); ok {
+		if err := todo.PriorityValidator(v); err != nil {
+			return &ValidationError{Name: "priority", err: fmt.Errorf(`ent: validator failed for field "Todo.priority": %w`, err)}
+		}
+	}
+	if _, ok := tu.mutation.UserID(); tu.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Todo.user"`)
+	}
+	return nil
+}
+
+func (tu *TodoUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := tu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(todo.Table, todo.Columns, sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt))
+	if ps := tu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := tu.mutation.Title(); ok {
+		_spec.SetField(todo.FieldTitle, field.TypeString, value)
+	}
+	if value, ok := tu.mutation.Status(); ok {
+		_spec.SetField(todo.FieldStatus, field.TypeString, value)
+	}
+	if value, ok := tu.mutation.CompletedAt(); ok {
+		_spec.SetField(todo.FieldCompletedAt, field.TypeTime, value)
+	}
+	if tu.mutation.CompletedAtCleared() {
+		_spec.ClearField(todo.FieldCompletedAt, field.TypeTime)
+	}
+	if value, ok := tu.mutation.Priority(); ok {
+		_spec.SetField(todo.FieldPriority, field.TypeEnum, value)
+	}
+	if value, ok := tu.mutation.Position(); ok {
+		_spec.SetField(todo.FieldPosition, field.TypeInt64, value)
+	}
+	if value, ok := tu.mutation.AddedPosition(); ok {
+		_spec.AddField(todo.FieldPosition, field.TypeInt64, value)
+	}
+	if value, ok := tu.mutation.StartAt(); ok {
+		_spec.SetField(todo.FieldStartAt, field.TypeTime, value)
+	}
+	if tu.mutation.StartAtCleared() {
+		_spec.ClearField(todo.FieldStartAt, field.TypeTime)
+	}
+	if value, ok := tu.mutation.DueAt(); ok {
+		_spec.SetField(todo.FieldDueAt, field.TypeTime, value)
+	}
+	if tu.mutation.DueAtCleared() {
+		_spec.ClearField(todo.FieldDueAt, field.TypeTime)
+	}
+	if value, ok := tu.mutation.AutoComplete(); ok {
+		_spec.SetField(todo.FieldAutoComplete, field.TypeBool, value)
+	}
+	if value, ok := tu.mutation.Recurrence(); ok {
+		_spec.SetField(todo.FieldRecurrence, field.TypeString, value)
+	}
+	if tu.mutation.RecurrenceCleared() {
+		_spec.ClearField(todo.FieldRecurrence, field.TypeString)
+	}
+	if value, ok := tu.mutation.SeriesID(); ok {
+		_spec.SetField(todo.FieldSeriesID, field.TypeInt, value)
+	}
+	if value, ok := tu.mutation.AddedSeriesID(); ok {
+		_spec.AddField(todo.FieldSeriesID, field.TypeInt, value)
+	}
+	if tu.mutation.SeriesIDCleared() {
+		_spec.ClearField(todo.FieldSeriesID, field.TypeInt)
+	}
+	if value, ok := tu.mutation.OccurrenceAt(); ok {
+		_spec.SetField(todo.FieldOccurrenceAt, field.TypeTime, value)
+	}
+	if tu.mutation.OccurrenceAtCleared() {
+		_spec.ClearField(todo.FieldOccurrenceAt, field.TypeTime)
+	}
+	if tu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.UserTable,
+			Columns: []string{todo.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.UserTable,
+			Columns: []string{todo.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if tu.mutation.ListCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.ListTable,
+			Columns: []string{todo.ListColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(list.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.ListIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.ListTable,
+			Columns: []string{todo.ListColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(list.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if tu.mutation.TagsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   todo.TagsTable,
+			Columns: todo.TagsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tag.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.RemovedTagsIDs(); len(nodes) > 0 && !tu.mutation.TagsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   todo.TagsTable,
+			Columns: todo.TagsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tag.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.TagsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   todo.TagsTable,
+			Columns: todo.TagsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tag.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if tu.mutation.ParentCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.ParentTable,
+			Columns: []string{todo.ParentColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.ParentIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.ParentTable,
+			Columns: []string{todo.ParentColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if tu.mutation.ChildrenCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   todo.ChildrenTable,
+			Columns: []string{todo.ChildrenColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.RemovedChildrenIDs(); len(nodes) > 0 && !tu.mutation.ChildrenCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   todo.ChildrenTable,
+			Columns: []string{todo.ChildrenColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tu.mutation.ChildrenIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   todo.ChildrenTable,
+			Columns: []string{todo.ChildrenColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, tu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{todo.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	tu.mutation.done = true
+	return n, nil
+}
+
+// TodoUpdateOne is the builder for updating a single Todo entity.
+type TodoUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *TodoMutation
+}
+
+// SetTitle sets the "title" field.
+func (tuo *TodoUpdateOne) SetTitle(s string) *TodoUpdateOne {
+	tuo.mutation.SetTitle(s)
+	return tuo
+}
+
+// SetNillableTitle sets the "title" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableTitle(s *string) *TodoUpdateOne {
+	if s != nil {
+		tuo.SetTitle(*s)
+	}
+	return tuo
+}
+
+// SetStatus sets the "status" field.
+func (tuo *TodoUpdateOne) SetStatus(s string) *TodoUpdateOne {
+	tuo.mutation.SetStatus(s)
+	return tuo
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableStatus(s *string) *TodoUpdateOne {
+	if s != nil {
+		tuo.SetStatus(*s)
+	}
+	return tuo
+}
+
+// SetCompletedAt sets the "completed_at" field.
+func (tuo *TodoUpdateOne) SetCompletedAt(t time.Time) *TodoUpdateOne {
+	tuo.mutation.SetCompletedAt(t)
+	return tuo
+}
+
+// SetNillableCompletedAt sets the "completed_at" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableCompletedAt(t *time.Time) *TodoUpdateOne {
+	if t != nil {
+		tuo.SetCompletedAt(*t)
+	}
+	return tuo
+}
+
+// ClearCompletedAt clears the value of the "completed_at" field.
+func (tuo *TodoUpdateOne) ClearCompletedAt() *TodoUpdateOne {
+	tuo.mutation.ClearCompletedAt()
+	return tuo
+}
+
+// SetPriority sets the "priority" field.
+func (tuo *TodoUpdateOne) SetPriority(t todo.Priority) *TodoUpdateOne {
+	tuo.mutation.SetPriority(t)
+	return tuo
+}
+
+// SetNillablePriority sets the "priority" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillablePriority(t *todo.Priority) *TodoUpdateOne {
+	if t != nil {
+		tuo.SetPriority(*t)
+	}
+	return tuo
+}
+
+// SetPosition sets the "position" field.
+func (tuo *TodoUpdateOne) SetPosition(i int64) *TodoUpdateOne {
+	tuo.mutation.ResetPosition()
+	tuo.mutation.SetPosition(i)
+	return tuo
+}
+
+// SetNillablePosition sets the "position" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillablePosition(i *int64) *TodoUpdateOne {
+	if i != nil {
+		tuo.SetPosition(*i)
+	}
+	return tuo
+}
+
+// AddPosition adds i to the "position" field.
+func (tuo *TodoUpdateOne) AddPosition(i int64) *TodoUpdateOne {
+	tuo.mutation.AddPosition(i)
+	return tuo
+}
+
+// SetStartAt sets the "start_at" field.
+func (tuo *TodoUpdateOne) SetStartAt(t time.Time) *TodoUpdateOne {
+	tuo.mutation.SetStartAt(t)
+	return tuo
+}
+
+// SetNillableStartAt sets the "start_at" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableStartAt(t *time.Time) *TodoUpdateOne {
+	if t != nil {
+		tuo.SetStartAt(*t)
+	}
+	return tuo
+}
+
+// ClearStartAt clears the value of the "start_at" field.
+func (tuo *TodoUpdateOne) ClearStartAt() *TodoUpdateOne {
+	tuo.mutation.ClearStartAt()
+	return tuo
+}
+
+// SetDueAt sets the "due_at" field.
+func (tuo *TodoUpdateOne) SetDueAt(t time.Time) *TodoUpdateOne {
+	tuo.mutation.SetDueAt(t)
+	return tuo
+}
+
+// SetNillableDueAt sets the "due_at" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableDueAt(t *time.Time) *TodoUpdateOne {
+	if t != nil {
+		tuo.SetDueAt(*t)
+	}
+	return tuo
+}
+
+// ClearDueAt clears the value of the "due_at" field.
+func (tuo *TodoUpdateOne) ClearDueAt() *TodoUpdateOne {
+	tuo.mutation.ClearDueAt()
+	return tuo
+}
+
+// SetListID sets the "list_id" field.
+func (tuo *TodoUpdateOne) SetListID(i int) *TodoUpdateOne {
+	tuo.mutation.SetListID(i)
+	return tuo
+}
+
+// SetNillableListID sets the "list_id" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableListID(i *int) *TodoUpdateOne {
+	if i != nil {
+		tuo.SetListID(*i)
+	}
+	return tuo
+}
+
+// ClearListID clears the value of the "list_id" field.
+func (tuo *TodoUpdateOne) ClearListID() *TodoUpdateOne {
+	tuo.mutation.ClearListID()
+	return tuo
+}
+
+// SetParentID sets the "parent_id" field.
+func (tuo *TodoUpdateOne) SetParentID(i int) *TodoUpdateOne {
+	tuo.mutation.SetParentID(i)
+	return tuo
+}
+
+// SetNillableParentID sets the "parent_id" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableParentID(i *int) *TodoUpdateOne {
+	if i != nil {
+		tuo.SetParentID(*i)
+	}
+	return tuo
+}
+
+// ClearParentID clears the value of the "parent_id" field.
+func (tuo *TodoUpdateOne) ClearParentID() *TodoUpdateOne {
+	tuo.mutation.ClearParentID()
+	return tuo
+}
+
+// SetAutoComplete sets the "auto_complete" field.
+func (tuo *TodoUpdateOne) SetAutoComplete(b bool) *TodoUpdateOne {
+	tuo.mutation.SetAutoComplete(b)
+	return tuo
+}
+
+// SetNillableAutoComplete sets the "auto_complete" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableAutoComplete(b *bool) *TodoUpdateOne {
+	if b != nil {
+		tuo.SetAutoComplete(*b)
+	}
+	return tuo
 }
 
-// Exec executes the query.
-func (tu *TodoUpdate) Exec(ctx context.Context) error {
-	_, err := tu.Save(ctx)
-	return err
+// SetRecurrence sets the "recurrence" field.
+func (tuo *TodoUpdateOne) SetRecurrence(s string) *TodoUpdateOne {
+	tuo.mutation.SetRecurrence(s)
+	return tuo
 }
 
-// ExecX is like Exec, but panics if an error occurs.
-func (tu *TodoUpdate) ExecX(ctx context.Context) {
-	if err := tu.Exec(ctx); err != nil {
-		panic(err)
+// SetNillableRecurrence sets the "recurrence" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableRecurrence(s *string) *TodoUpdateOne {
+	if s != nil {
+		tuo.SetRecurrence(*s)
 	}
+	return tuo
 }
 
-// check runs all checks and user-defined validators on the builder.
-func (tu *TodoUpdate) check() error {
-	if v, ok := tu.mutation.Status(); ok {
-		if err := todo.StatusValidator(v); err != nil {
-			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Todo.status": %w`, err)}
-		}
-	}
-	if _, ok := tu.mutation.UserID(); tu.mutation.UserCleared() && !ok {
-		return errors.New(`ent: clearing a required unique edge "Todo.user"`)
-	}
-	return nil
+// ClearRecurrence clears the value of the "recurrence" field.
+func (tuo *TodoUpdateOne) ClearRecurrence() *TodoUpdateOne {
+	tuo.mutation.ClearRecurrence()
+	return tuo
 }
 
-func (tu *TodoUpdate) sqlSave(ctx context.Context) (n int, err error) {
-	if err := tu.check(); err != nil {
-		return n, err
-	}
-	_spec := sqlgraph.NewUpdateSpec(todo.Table, todo.Columns, sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt))
-	if ps := tu.mutation.predicates; len(ps) > 0 {
-		_spec.Predicate = func(selector *sql.Selector) {
-			for i := range ps {
-				ps[i](selector)
-			}
-		}
-	}
-	if value, ok := tu.mutation.Title(); ok {
-		_spec.SetField(todo.FieldTitle, field.TypeString, value)
-	}
-	if value, ok := tu.mutation.Status(); ok {
-		_spec.SetField(todo.FieldStatus, field.TypeEnum, value)
-	}
-	if tu.mutation.UserCleared() {
-		edge := &sqlgraph.EdgeSpec{
-			Rel:     sqlgraph.M2O,
-			Inverse: true,
-			Table:   todo.UserTable,
-			Columns: []string{todo.UserColumn},
-			Bidi:    false,
-			Target: &sqlgraph.EdgeTarget{
-				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
-			},
-		}
-		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
-	}
-	if nodes := tu.mutation.UserIDs(); len(nodes) > 0 {
-		edge := &sqlgraph.EdgeSpec{
-			Rel:     sqlgraph.M2O,
-			Inverse: true,
-			Table:   todo.UserTable,
-			Columns: []string{todo.UserColumn},
-			Bidi:    false,
-			Target: &sqlgraph.EdgeTarget{
-				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
-			},
-		}
-		for _, k := range nodes {
-			edge.Target.Nodes = append(edge.Target.Nodes, k)
-		}
-		_spec.Edges.Add = append(_spec.Edges.Add, edge)
-	}
-	if n, err = sqlgraph.UpdateNodes(ctx, tu.driver, _spec); err != nil {
-		if _, ok := err.(*sqlgraph.NotFoundError); ok {
-			err = &NotFoundError{todo.Label}
-		} else if sqlgraph.IsConstraintError(err) {
-			err = &ConstraintError{msg: err.Error(), wrap: err}
-		}
-		return 0, err
-	}
-	tu.mutation.done = true
-	return n, nil
+// SetSeriesID sets the "series_id" field.
+func (tuo *TodoUpdateOne) SetSeriesID(i int) *TodoUpdateOne {
+	tuo.mutation.ResetSeriesID()
+	tuo.mutation.SetSeriesID(i)
+	return tuo
 }
 
-// TodoUpdateOne is the builder for updating a single Todo entity.
-type TodoUpdateOne struct {
-	config
-	fields   []string
-	hooks    []Hook
-	mutation *TodoMutation
+// SetNillableSeriesID sets the "series_id" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableSeriesID(i *int) *TodoUpdateOne {
+	if i != nil {
+		tuo.SetSeriesID(*i)
+	}
+	return tuo
 }
 
-// SetTitle sets the "title" field.
-func (tuo *TodoUpdateOne) SetTitle(s string) *TodoUpdateOne {
-	tuo.mutation.SetTitle(s)
+// AddSeriesID adds i to the "series_id" field.
+func (tuo *TodoUpdateOne) AddSeriesID(i int) *TodoUpdateOne {
+	tuo.mutation.AddSeriesID(i)
 	return tuo
 }
 
-// SetNillableTitle sets the "title" field if the given value is not nil.
-func (tuo *TodoUpdateOne) SetNillableTitle(s *string) *TodoUpdateOne {
-	if s != nil {
-		tuo.SetTitle(*s)
-	}
+// ClearSeriesID clears the value of the "series_id" field.
+func (tuo *TodoUpdateOne) ClearSeriesID() *TodoUpdateOne {
+	tuo.mutation.ClearSeriesID()
 	return tuo
 }
 
-// SetStatus sets the "status" field.
-func (tuo *TodoUpdateOne) SetStatus(t todo.Status) *TodoUpdateOne {
-	tuo.mutation.SetStatus(t)
+// SetOccurrenceAt sets the "occurrence_at" field.
+func (tuo *TodoUpdateOne) SetOccurrenceAt(t time.Time) *TodoUpdateOne {
+	tuo.mutation.SetOccurrenceAt(t)
 	return tuo
 }
 
-// SetNillableStatus sets the "status" field if the given value is not nil.
-func (tuo *TodoUpdateOne) SetNillableStatus(t *todo.Status) *TodoUpdateOne {
+// SetNillableOccurrenceAt sets the "occurrence_at" field if the given value is not nil.
+func (tuo *TodoUpdateOne) SetNillableOccurrenceAt(t *time.Time) *TodoUpdateOne {
 	if t != nil {
-		tuo.SetStatus(*t)
+		tuo.SetOccurrenceAt(*t)
 	}
 	return tuo
 }
 
+// ClearOccurrenceAt clears the value of the "occurrence_at" field.
+func (tuo *TodoUpdateOne) ClearOccurrenceAt() *TodoUpdateOne {
+	tuo.mutation.ClearOccurrenceAt()
+	return tuo
+}
+
 // SetUserID sets the "user" edge to the User entity by ID.
 func (tuo *TodoUpdateOne) SetUserID(id int) *TodoUpdateOne {
 	tuo.mutation.SetUserID(id)
@@ -224,6 +952,46 @@ func (tuo *TodoUpdateOne) SetUser(u *User) *TodoUpdateOne {
 	return tuo.SetUserID(u.ID)
 }
 
+// SetList sets the "list" edge to the List entity.
+func (tuo *TodoUpdateOne) SetList(l *List) *TodoUpdateOne {
+	return tuo.SetListID(l.ID)
+}
+
+// AddTagIDs adds the "tags" edge to the Tag entity by IDs.
+func (tuo *TodoUpdateOne) AddTagIDs(ids ...int) *TodoUpdateOne {
+	tuo.mutation.AddTagIDs(ids...)
+	return tuo
+}
+
+// AddTags adds the "tags" edges to the Tag entity.
+func (tuo *TodoUpdateOne) AddTags(t ...*Tag) *TodoUpdateOne {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tuo.AddTagIDs(ids...)
+}
+
+// SetParent sets the "parent" edge to the Todo entity.
+func (tuo *TodoUpdateOne) SetParent(t *Todo) *TodoUpdateOne {
+	return tuo.SetParentID(t.ID)
+}
+
+// AddChildIDs adds the "children" edge to the Todo entity by IDs.
+func (tuo *TodoUpdateOne) AddChildIDs(ids ...int) *TodoUpdateOne {
+	tuo.mutation.AddChildIDs(ids...)
+	return tuo
+}
+
+// AddChildren adds the "children" edges to the Todo entity.
+func (tuo *TodoUpdateOne) AddChildren(t ...*Todo) *TodoUpdateOne {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tuo.AddChildIDs(ids...)
+}
+
 // Mutation returns the TodoMutation object of the builder.
 func (tuo *TodoUpdateOne) Mutation() *TodoMutation {
 	return tuo.mutation
@@ -235,6 +1003,60 @@ func (tuo *TodoUpdateOne) ClearUser() *TodoUpdateOne {
 	return tuo
 }
 
+// ClearList clears the "list" edge to the List entity.
+func (tuo *TodoUpdateOne) ClearList() *TodoUpdateOne {
+	tuo.mutation.ClearList()
+	return tuo
+}
+
+// ClearTags clears all "tags" edges to the Tag entity.
+func (tuo *TodoUpdateOne) ClearTags() *TodoUpdateOne {
+	tuo.mutation.ClearTags()
+	return tuo
+}
+
+// RemoveTagIDs removes the "tags" edge to Tag entities by IDs.
+func (tuo *TodoUpdateOne) RemoveTagIDs(ids ...int) *TodoUpdateOne {
+	tuo.mutation.RemoveTagIDs(ids...)
+	return tuo
+}
+
+// RemoveTags removes "tags" edges to Tag entities.
+func (tuo *TodoUpdateOne) RemoveTags(t ...*Tag) *TodoUpdateOne {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tuo.RemoveTagIDs(ids...)
+}
+
+// ClearParent clears the "parent" edge to the Todo entity.
+func (tuo *TodoUpdateOne) ClearParent() *TodoUpdateOne {
+	tuo.mutation.ClearParent()
+	return tuo
+}
+
+// ClearChildren clears all "children" edges to the Todo entity.
+func (tuo *TodoUpdateOne) ClearChildren() *TodoUpdateOne {
+	tuo.mutation.ClearChildren()
+	return tuo
+}
+
+// RemoveChildIDs removes the "children" edge to Todo entities by IDs.
+func (tuo *TodoUpdateOne) RemoveChildIDs(ids ...int) *TodoUpdateOne {
+	tuo.mutation.RemoveChildIDs(ids...)
+	return tuo
+}
+
+// RemoveChildren removes "children" edges to Todo entities.
+func (tuo *TodoUpdateOne) RemoveChildren(t ...*Todo) *TodoUpdateOne {
+	ids := make([]int, len(t))
+	for i := range t {
+		ids[i] = t[i].ID
+	}
+	return tuo.RemoveChildIDs(ids...)
+}
+
 // Where appends a list predicates to the TodoUpdate builder.
 func (tuo *TodoUpdateOne) Where(ps ...predicate.Todo) *TodoUpdateOne {
 	tuo.mutation.Where(ps...)
@@ -277,9 +1099,9 @@ func (tuo *TodoUpdateOne) ExecX(ctx context.Context) {
 
 // check runs all checks and user-defined validators on the builder.
 func (tuo *TodoUpdateOne) check() error {
-	if v, ok := tuo.mutation.Status(); ok {
-		if err := todo.StatusValidator(v); err != nil {
-			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Todo.status": %w`, err)}
+	if v, ok := tuo.mutation.Priority(); ok {
+		if err := todo.PriorityValidator(v); err != nil {
+			return &ValidationError{Name: "priority", err: fmt.Errorf(`ent: validator failed for field "Todo.priority": %w`, err)}
 		}
 	}
 	if _, ok := tuo.mutation.UserID(); tuo.mutation.UserCleared() && !ok {
@@ -321,7 +1143,58 @@ func (tuo *TodoUpdateOne) sqlSave(ctx context.Context) (_node *Todo, err error)
 		_spec.SetField(todo.FieldTitle, field.TypeString, value)
 	}
 	if value, ok := tuo.mutation.Status(); ok {
-		_spec.SetField(todo.FieldStatus, field.TypeEnum, value)
+		_spec.SetField(todo.FieldStatus, field.TypeString, value)
+	}
+	if value, ok := tuo.mutation.CompletedAt(); ok {
+		_spec.SetField(todo.FieldCompletedAt, field.TypeTime, value)
+	}
+	if tuo.mutation.CompletedAtCleared() {
+		_spec.ClearField(todo.FieldCompletedAt, field.TypeTime)
+	}
+	if value, ok := tuo.mutation.Priority(); ok {
+		_spec.SetField(todo.FieldPriority, field.TypeEnum, value)
+	}
+	if value, ok := tuo.mutation.Position(); ok {
+		_spec.SetField(todo.FieldPosition, field.TypeInt64, value)
+	}
+	if value, ok := tuo.mutation.AddedPosition(); ok {
+		_spec.AddField(todo.FieldPosition, field.TypeInt64, value)
+	}
+	if value, ok := tuo.mutation.StartAt(); ok {
+		_spec.SetField(todo.FieldStartAt, field.TypeTime, value)
+	}
+	if tuo.mutation.StartAtCleared() {
+		_spec.ClearField(todo.FieldStartAt, field.TypeTime)
+	}
+	if value, ok := tuo.mutation.DueAt(); ok {
+		_spec.SetField(todo.FieldDueAt, field.TypeTime, value)
+	}
+	if tuo.mutation.DueAtCleared() {
+		_spec.ClearField(todo.FieldDueAt, field.TypeTime)
+	}
+	if value, ok := tuo.mutation.AutoComplete(); ok {
+		_spec.SetField(todo.FieldAutoComplete, field.TypeBool, value)
+	}
+	if value, ok := tuo.mutation.Recurrence(); ok {
+		_spec.SetField(todo.FieldRecurrence, field.TypeString, value)
+	}
+	if tuo.mutation.RecurrenceCleared() {
+		_spec.ClearField(todo.FieldRecurrence, field.TypeString)
+	}
+	if value, ok := tuo.mutation.SeriesID(); ok {
+		_spec.SetField(todo.FieldSeriesID, field.TypeInt, value)
+	}
+	if value, ok := tuo.mutation.AddedSeriesID(); ok {
+		_spec.AddField(todo.FieldSeriesID, field.TypeInt, value)
+	}
+	if tuo.mutation.SeriesIDCleared() {
+		_spec.ClearField(todo.FieldSeriesID, field.TypeInt)
+	}
+	if value, ok := tuo.mutation.OccurrenceAt(); ok {
+		_spec.SetField(todo.FieldOccurrenceAt, field.TypeTime, value)
+	}
+	if tuo.mutation.OccurrenceAtCleared() {
+		_spec.ClearField(todo.FieldOccurrenceAt, field.TypeTime)
 	}
 	if tuo.mutation.UserCleared() {
 		edge := &sqlgraph.EdgeSpec{
@@ -352,6 +1225,154 @@ func (tuo *TodoUpdateOne) sqlSave(ctx context.Context) (_node *Todo, err error)
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
+	if tuo.mutation.ListCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.ListTable,
+			Columns: []string{todo.ListColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(list.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tuo.mutation.ListIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.ListTable,
+			Columns: []string{todo.ListColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(list.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if tuo.mutation.TagsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   todo.TagsTable,
+			Columns: todo.TagsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tag.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tuo.mutation.RemovedTagsIDs(); len(nodes) > 0 && !tuo.mutation.TagsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   todo.TagsTable,
+			Columns: todo.TagsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tag.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tuo.mutation.TagsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   todo.TagsTable,
+			Columns: todo.TagsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(tag.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if tuo.mutation.ParentCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.ParentTable,
+			Columns: []string{todo.ParentColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tuo.mutation.ParentIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   todo.ParentTable,
+			Columns: []string{todo.ParentColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if tuo.mutation.ChildrenCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   todo.ChildrenTable,
+			Columns: []string{todo.ChildrenColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tuo.mutation.RemovedChildrenIDs(); len(nodes) > 0 && !tuo.mutation.ChildrenCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   todo.ChildrenTable,
+			Columns: []string{todo.ChildrenColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := tuo.mutation.ChildrenIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   todo.ChildrenTable,
+			Columns: []string{todo.ChildrenColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(todo.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
 	_node = &Todo{config: tuo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
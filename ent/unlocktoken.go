@@ -0,0 +1,182 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"todo/ent/unlocktoken"
+	"todo/ent/user"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+// UnlockToken is the model entity for the UnlockToken schema.
+type UnlockToken struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// TokenHash holds the value of the "token_hash" field.
+	TokenHash string `json:"-"`
+	// ExpiresAt holds the value of the "expires_at" field.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UsedAt holds the value of the "used_at" field.
+	UsedAt *time.Time `json:"used_at,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the UnlockTokenQuery when eager-loading is set.
+	Edges              UnlockTokenEdges `json:"edges"`
+	user_unlock_tokens *int
+	selectValues       sql.SelectValues
+}
+
+// UnlockTokenEdges holds the relations/edges for other nodes in the graph.
+type UnlockTokenEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e UnlockTokenEdges) UserOrErr() (*User, error) {
+	if e.loadedTypes[0] {
+		if e.User == nil {
+			// Edge was loaded but was not found.
+			return nil, &NotFoundError{label: user.Label}
+		}
+		return e.User, nil
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*UnlockToken) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case unlocktoken.FieldID:
+			values[i] = new(sql.NullInt64)
+		case unlocktoken.FieldTokenHash:
+			values[i] = new(sql.NullString)
+		case unlocktoken.FieldExpiresAt, unlocktoken.FieldCreatedAt, unlocktoken.FieldUsedAt:
+			values[i] = new(sql.NullTime)
+		case unlocktoken.ForeignKeys[0]: // user_unlock_tokens
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the UnlockToken fields.
+func (ut *UnlockToken) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case unlocktoken.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			ut.ID = int(value.Int64)
+		case unlocktoken.FieldTokenHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field token_hash", values[i])
+			} else if value.Valid {
+				ut.TokenHash = value.String
+			}
+		case unlocktoken.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				ut.ExpiresAt = value.Time
+			}
+		case unlocktoken.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				ut.CreatedAt = value.Time
+			}
+		case unlocktoken.FieldUsedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field used_at", values[i])
+			} else if value.Valid {
+				ut.UsedAt = new(time.Time)
+				*ut.UsedAt = value.Time
+			}
+		case unlocktoken.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field user_unlock_tokens", value)
+			} else if value.Valid {
+				ut.user_unlock_tokens = new(int)
+				*ut.user_unlock_tokens = int(value.Int64)
+			}
+		default:
+			ut.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the UnlockToken.
+// This includes values selected through modifiers, order, etc.
+func (ut *UnlockToken) Value(name string) (ent.Value, error) {
+	return ut.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the UnlockToken entity.
+func (ut *UnlockToken) QueryUser() *UserQuery {
+	return NewUnlockTokenClient(ut.config).QueryUser(ut)
+}
+
+// Update returns a builder for updating this UnlockToken.
+// Note that you need to call UnlockToken.Unwrap() before calling this method if this UnlockToken
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (ut *UnlockToken) Update() *UnlockTokenUpdateOne {
+	return NewUnlockTokenClient(ut.config).UpdateOne(ut)
+}
+
+// Unwrap unwraps the UnlockToken entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (ut *UnlockToken) Unwrap() *UnlockToken {
+	_tx, ok := ut.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: UnlockToken is not a transactional entity")
+	}
+	ut.config.driver = _tx.drv
+	return ut
+}
+
+// String implements the fmt.Stringer.
+func (ut *UnlockToken) String() string {
+	var builder strings.Builder
+	builder.WriteString("UnlockToken(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", ut.ID))
+	builder.WriteString("token_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("expires_at=")
+	builder.WriteString(ut.ExpiresAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(ut.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := ut.UsedAt; v != nil {
+		builder.WriteString("used_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// UnlockTokens is a parsable slice of UnlockToken.
+type UnlockTokens []*UnlockToken
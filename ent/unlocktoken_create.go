@@ -0,0 +1,278 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo/ent/unlocktoken"
+	"todo/ent/user"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// UnlockTokenCreate is the builder for creating a UnlockToken entity.
+type UnlockTokenCreate struct {
+	config
+	mutation *UnlockTokenMutation
+	hooks    []Hook
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (utc *UnlockTokenCreate) SetTokenHash(s string) *UnlockTokenCreate {
+	utc.mutation.SetTokenHash(s)
+	return utc
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (utc *UnlockTokenCreate) SetExpiresAt(t time.Time) *UnlockTokenCreate {
+	utc.mutation.SetExpiresAt(t)
+	return utc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (utc *UnlockTokenCreate) SetCreatedAt(t time.Time) *UnlockTokenCreate {
+	utc.mutation.SetCreatedAt(t)
+	return utc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (utc *UnlockTokenCreate) SetNillableCreatedAt(t *time.Time) *UnlockTokenCreate {
+	if t != nil {
+		utc.SetCreatedAt(*t)
+	}
+	return utc
+}
+
+// SetUsedAt sets the "used_at" field.
+func (utc *UnlockTokenCreate) SetUsedAt(t time.Time) *UnlockTokenCreate {
+	utc.mutation.SetUsedAt(t)
+	return utc
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (utc *UnlockTokenCreate) SetNillableUsedAt(t *time.Time) *UnlockTokenCreate {
+	if t != nil {
+		utc.SetUsedAt(*t)
+	}
+	return utc
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (utc *UnlockTokenCreate) SetUserID(id int) *UnlockTokenCreate {
+	utc.mutation.SetUserID(id)
+	return utc
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (utc *UnlockTokenCreate) SetUser(u *User) *UnlockTokenCreate {
+	return utc.SetUserID(u.ID)
+}
+
+// Mutation returns the UnlockTokenMutation object of the builder.
+func (utc *UnlockTokenCreate) Mutation() *UnlockTokenMutation {
+	return utc.mutation
+}
+
+// Save creates the UnlockToken in the database.
+func (utc *UnlockTokenCreate) Save(ctx context.Context) (*UnlockToken, error) {
+	utc.defaults()
+	return withHooks(ctx, utc.sqlSave, utc.mutation, utc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (utc *UnlockTokenCreate) SaveX(ctx context.Context) *UnlockToken {
+	v, err := utc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (utc *UnlockTokenCreate) Exec(ctx context.Context) error {
+	_, err := utc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (utc *UnlockTokenCreate) ExecX(ctx context.Context) {
+	if err := utc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (utc *UnlockTokenCreate) defaults() {
+	if _, ok := utc.mutation.CreatedAt(); !ok {
+		v := unlocktoken.DefaultCreatedAt()
+		utc.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (utc *UnlockTokenCreate) check() error {
+	if _, ok := utc.mutation.TokenHash(); !ok {
+		return &ValidationError{Name: "token_hash", err: errors.New(`ent: missing required field "UnlockToken.token_hash"`)}
+	}
+	if _, ok := utc.mutation.ExpiresAt(); !ok {
+		return &ValidationError{Name: "expires_at", err: errors.New(`ent: missing required field "UnlockToken.expires_at"`)}
+	}
+	if _, ok := utc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "UnlockToken.created_at"`)}
+	}
+	if _, ok := utc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "UnlockToken.user"`)}
+	}
+	return nil
+}
+
+func (utc *UnlockTokenCreate) sqlSave(ctx context.Context) (*UnlockToken, error) {
+	if err := utc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := utc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, utc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	utc.mutation.id = &_node.ID
+	utc.mutation.done = true
+	return _node, nil
+}
+
+func (utc *UnlockTokenCreate) createSpec() (*UnlockToken, *sqlgraph.CreateSpec) {
+	var (
+		_node = &UnlockToken{config: utc.config}
+		_spec = sqlgraph.NewCreateSpec(unlocktoken.Table, sqlgraph.NewFieldSpec(unlocktoken.FieldID, field.TypeInt))
+	)
+	if value, ok := utc.mutation.TokenHash(); ok {
+		_spec.SetField(unlocktoken.FieldTokenHash, field.TypeString, value)
+		_node.TokenHash = value
+	}
+	if value, ok := utc.mutation.ExpiresAt(); ok {
+		_spec.SetField(unlocktoken.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = value
+	}
+	if value, ok := utc.mutation.CreatedAt(); ok {
+		_spec.SetField(unlocktoken.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := utc.mutation.UsedAt(); ok {
+		_spec.SetField(unlocktoken.FieldUsedAt, field.TypeTime, value)
+		_node.UsedAt = &value
+	}
+	if nodes := utc.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   unlocktoken.UserTable,
+			Columns: []string{unlocktoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.user_unlock_tokens = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// UnlockTokenCreateBulk is the builder for creating many UnlockToken entities in bulk.
+type UnlockTokenCreateBulk struct {
+	config
+	err      error
+	builders []*UnlockTokenCreate
+}
+
+// Save creates the UnlockToken entities in the database.
+func (utcb *UnlockTokenCreateBulk) Save(ctx context.Context) ([]*UnlockToken, error) {
+	if utcb.err != nil {
+		return nil, utcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(utcb.builders))
+	nodes := make([]*UnlockToken, len(utcb.builders))
+	mutators := make([]Mutator, len(utcb.builders))
+	for i := range utcb.builders {
+		func(i int, root context.Context) {
+			builder := utcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*UnlockTokenMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, utcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, utcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, utcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (utcb *UnlockTokenCreateBulk) SaveX(ctx context.Context) []*UnlockToken {
+	v, err := utcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (utcb *UnlockTokenCreateBulk) Exec(ctx context.Context) error {
+	_, err := utcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (utcb *UnlockTokenCreateBulk) ExecX(ctx context.Context) {
+	if err := utcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
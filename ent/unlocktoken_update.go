@@ -0,0 +1,411 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo/ent/predicate"
+	"todo/ent/unlocktoken"
+	"todo/ent/user"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// UnlockTokenUpdate is the builder for updating UnlockToken entities.
+type UnlockTokenUpdate struct {
+	config
+	hooks    []Hook
+	mutation *UnlockTokenMutation
+}
+
+// Where appends a list predicates to the UnlockTokenUpdate builder.
+func (utu *UnlockTokenUpdate) Where(ps ...predicate.UnlockToken) *UnlockTokenUpdate {
+	utu.mutation.Where(ps...)
+	return utu
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (utu *UnlockTokenUpdate) SetTokenHash(s string) *UnlockTokenUpdate {
+	utu.mutation.SetTokenHash(s)
+	return utu
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (utu *UnlockTokenUpdate) SetNillableTokenHash(s *string) *UnlockTokenUpdate {
+	if s != nil {
+		utu.SetTokenHash(*s)
+	}
+	return utu
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (utu *UnlockTokenUpdate) SetExpiresAt(t time.Time) *UnlockTokenUpdate {
+	utu.mutation.SetExpiresAt(t)
+	return utu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (utu *UnlockTokenUpdate) SetNillableExpiresAt(t *time.Time) *UnlockTokenUpdate {
+	if t != nil {
+		utu.SetExpiresAt(*t)
+	}
+	return utu
+}
+
+// SetUsedAt sets the "used_at" field.
+func (utu *UnlockTokenUpdate) SetUsedAt(t time.Time) *UnlockTokenUpdate {
+	utu.mutation.SetUsedAt(t)
+	return utu
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (utu *UnlockTokenUpdate) SetNillableUsedAt(t *time.Time) *UnlockTokenUpdate {
+	if t != nil {
+		utu.SetUsedAt(*t)
+	}
+	return utu
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (utu *UnlockTokenUpdate) ClearUsedAt() *UnlockTokenUpdate {
+	utu.mutation.ClearUsedAt()
+	return utu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (utu *UnlockTokenUpdate) SetUserID(id int) *UnlockTokenUpdate {
+	utu.mutation.SetUserID(id)
+	return utu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (utu *UnlockTokenUpdate) SetUser(u *User) *UnlockTokenUpdate {
+	return utu.SetUserID(u.ID)
+}
+
+// Mutation returns the UnlockTokenMutation object of the builder.
+func (utu *UnlockTokenUpdate) Mutation() *UnlockTokenMutation {
+	return utu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (utu *UnlockTokenUpdate) ClearUser() *UnlockTokenUpdate {
+	utu.mutation.ClearUser()
+	return utu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (utu *UnlockTokenUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, utu.sqlSave, utu.mutation, utu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (utu *UnlockTokenUpdate) SaveX(ctx context.Context) int {
+	affected, err := utu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (utu *UnlockTokenUpdate) Exec(ctx context.Context) error {
+	_, err := utu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (utu *UnlockTokenUpdate) ExecX(ctx context.Context) {
+	if err := utu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (utu *UnlockTokenUpdate) check() error {
+	if _, ok := utu.mutation.UserID(); utu.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "UnlockToken.user"`)
+	}
+	return nil
+}
+
+func (utu *UnlockTokenUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := utu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(unlocktoken.Table, unlocktoken.Columns, sqlgraph.NewFieldSpec(unlocktoken.FieldID, field.TypeInt))
+	if ps := utu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := utu.mutation.TokenHash(); ok {
+		_spec.SetField(unlocktoken.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := utu.mutation.ExpiresAt(); ok {
+		_spec.SetField(unlocktoken.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := utu.mutation.UsedAt(); ok {
+		_spec.SetField(unlocktoken.FieldUsedAt, field.TypeTime, value)
+	}
+	if utu.mutation.UsedAtCleared() {
+		_spec.ClearField(unlocktoken.FieldUsedAt, field.TypeTime)
+	}
+	if utu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   unlocktoken.UserTable,
+			Columns: []string{unlocktoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := utu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   unlocktoken.UserTable,
+			Columns: []string{unlocktoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, utu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{unlocktoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	utu.mutation.done = true
+	return n, nil
+}
+
+// UnlockTokenUpdateOne is the builder for updating a single UnlockToken entity.
+type UnlockTokenUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *UnlockTokenMutation
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (utuo *UnlockTokenUpdateOne) SetTokenHash(s string) *UnlockTokenUpdateOne {
+	utuo.mutation.SetTokenHash(s)
+	return utuo
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (utuo *UnlockTokenUpdateOne) SetNillableTokenHash(s *string) *UnlockTokenUpdateOne {
+	if s != nil {
+		utuo.SetTokenHash(*s)
+	}
+	return utuo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (utuo *UnlockTokenUpdateOne) SetExpiresAt(t time.Time) *UnlockTokenUpdateOne {
+	utuo.mutation.SetExpiresAt(t)
+	return utuo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (utuo *UnlockTokenUpdateOne) SetNillableExpiresAt(t *time.Time) *UnlockTokenUpdateOne {
+	if t != nil {
+		utuo.SetExpiresAt(*t)
+	}
+	return utuo
+}
+
+// SetUsedAt sets the "used_at" field.
+func (utuo *UnlockTokenUpdateOne) SetUsedAt(t time.Time) *UnlockTokenUpdateOne {
+	utuo.mutation.SetUsedAt(t)
+	return utuo
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (utuo *UnlockTokenUpdateOne) SetNillableUsedAt(t *time.Time) *UnlockTokenUpdateOne {
+	if t != nil {
+		utuo.SetUsedAt(*t)
+	}
+	return utuo
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (utuo *UnlockTokenUpdateOne) ClearUsedAt() *UnlockTokenUpdateOne {
+	utuo.mutation.ClearUsedAt()
+	return utuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (utuo *UnlockTokenUpdateOne) SetUserID(id int) *UnlockTokenUpdateOne {
+	utuo.mutation.SetUserID(id)
+	return utuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (utuo *UnlockTokenUpdateOne) SetUser(u *User) *UnlockTokenUpdateOne {
+	return utuo.SetUserID(u.ID)
+}
+
+// Mutation returns the UnlockTokenMutation object of the builder.
+func (utuo *UnlockTokenUpdateOne) Mutation() *UnlockTokenMutation {
+	return utuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (utuo *UnlockTokenUpdateOne) ClearUser() *UnlockTokenUpdateOne {
+	utuo.mutation.ClearUser()
+	return utuo
+}
+
+// Where appends a list predicates to the UnlockTokenUpdate builder.
+func (utuo *UnlockTokenUpdateOne) Where(ps ...predicate.UnlockToken) *UnlockTokenUpdateOne {
+	utuo.mutation.Where(ps...)
+	return utuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (utuo *UnlockTokenUpdateOne) Select(field string, fields ...string) *UnlockTokenUpdateOne {
+	utuo.fields = append([]string{field}, fields...)
+	return utuo
+}
+
+// Save executes the query and returns the updated UnlockToken entity.
+func (utuo *UnlockTokenUpdateOne) Save(ctx context.Context) (*UnlockToken, error) {
+	return withHooks(ctx, utuo.sqlSave, utuo.mutation, utuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (utuo *UnlockTokenUpdateOne) SaveX(ctx context.Context) *UnlockToken {
+	node, err := utuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (utuo *UnlockTokenUpdateOne) Exec(ctx context.Context) error {
+	_, err := utuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (utuo *UnlockTokenUpdateOne) ExecX(ctx context.Context) {
+	if err := utuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (utuo *UnlockTokenUpdateOne) check() error {
+	if _, ok := utuo.mutation.UserID(); utuo.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "UnlockToken.user"`)
+	}
+	return nil
+}
+
+func (utuo *UnlockTokenUpdateOne) sqlSave(ctx context.Context) (_node *UnlockToken, err error) {
+	if err := utuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(unlocktoken.Table, unlocktoken.Columns, sqlgraph.NewFieldSpec(unlocktoken.FieldID, field.TypeInt))
+	id, ok := utuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "UnlockToken.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := utuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, unlocktoken.FieldID)
+		for _, f := range fields {
+			if !unlocktoken.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != unlocktoken.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := utuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := utuo.mutation.TokenHash(); ok {
+		_spec.SetField(unlocktoken.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := utuo.mutation.ExpiresAt(); ok {
+		_spec.SetField(unlocktoken.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := utuo.mutation.UsedAt(); ok {
+		_spec.SetField(unlocktoken.FieldUsedAt, field.TypeTime, value)
+	}
+	if utuo.mutation.UsedAtCleared() {
+		_spec.ClearField(unlocktoken.FieldUsedAt, field.TypeTime)
+	}
+	if utuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   unlocktoken.UserTable,
+			Columns: []string{unlocktoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := utuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   unlocktoken.UserTable,
+			Columns: []string{unlocktoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &UnlockToken{config: utuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, utuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{unlocktoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	utuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestLogMailer tests that sent messages are written out in full
+func TestLogMailer(t *testing.T) {
+	var buf bytes.Buffer
+	var m Mailer = NewLogMailer(&buf)
+
+	msg := Message{To: "alice@example.com", Subject: "Hello", Body: "Reset link: https://example.com/reset?token=abc"}
+	if err := m.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	for _, want := range []string{msg.To, msg.Subject, msg.Body} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output %q does not contain %q", buf.String(), want)
+		}
+	}
+}
+
+// TestSMTPMailerRejectsHeaderInjection tests that line breaks in headers are refused before sending
+func TestSMTPMailerRejectsHeaderInjection(t *testing.T) {
+	m := NewSMTPMailer("localhost:25", "", "", "noreply@example.com")
+	msg := Message{To: "alice@example.com\r\nBcc: eve@example.com", Subject: "Hello", Body: "Hi"}
+	if err := m.Send(context.Background(), msg); err == nil {
+		t.Error("Send succeeded, want error")
+	}
+}
@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPMailer sends emails through an SMTP server.
+type SMTPMailer struct {
+	// Addr is the host:port of the SMTP server
+	Addr string
+	// From is the sender address
+	From string
+	// Auth is optional, e.g. smtp.PlainAuth for servers requiring a login
+	Auth smtp.Auth
+}
+
+// NewSMTPMailer returns a mailer for the SMTP server at addr, authenticating with
+// PLAIN auth when a username is given.
+func NewSMTPMailer(addr, username, password, from string) *SMTPMailer {
+	m := &SMTPMailer{Addr: addr, From: from}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		m.Auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	// Header values must not contain line breaks or they could inject headers
+	for _, v := range []string{m.From, msg.To, msg.Subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("mailer: invalid header value %q", v)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	// net/smtp has no context support, so only check for cancellation up front
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(m.Addr, m.Auth, m.From, []string{msg.To}, []byte(b.String()))
+}
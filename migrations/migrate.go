@@ -2,11 +2,20 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	auth "todo/auth"
 	"todo/ent"
+	"todo/ent/migrate"
+	_ "todo/ent/runtime"
+	"todo/ent/user"
+	"todo/viewer"
 
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
 	_ "github.com/lib/pq"
 )
 
@@ -14,18 +23,106 @@ var (
 	PG_USER     = os.Getenv("PG_USER")
 	PG_PASSWORD = os.Getenv("PG_PASSWORD")
 	PG_DB       = os.Getenv("PG_DB")
+	// Comma separated emails of existing accounts to promote to administrator, which
+	// is how the first administrator is made
+	ADMIN_EMAILS = os.Getenv("ADMIN_EMAILS")
 )
 
 func main() {
 	connectionString := fmt.Sprintf("host=localhost port=5432 user=%s dbname=%s password=%s sslmode=disable", PG_USER, PG_DB, PG_PASSWORD)
-	client, err := ent.Open("postgres", connectionString)
+	db, err := sql.Open("postgres", connectionString)
 	if err != nil {
 		log.Fatalf("failed opening connection to pg: %v", err)
 	}
+	client := ent.NewClient(ent.Driver(entsql.OpenDB(dialect.Postgres, db)))
 	defer client.Close()
 
-	// Run auto migration tool
-	if err := client.Schema.Create(context.Background()); err != nil {
+	// Migrations may touch every user's data
+	ctx := viewer.SystemContext(context.Background())
+
+	// Data migrations that must run before the schema is diffed
+	if err := migrateTodoStatuses(ctx, db); err != nil {
+		log.Fatalf("failed migrating todo statuses: %v", err)
+	}
+	if err := migrateRefreshTokenSessions(ctx, db); err != nil {
+		log.Fatalf("failed migrating refresh tokens: %v", err)
+	}
+
+	// Run auto migration tool. Dropping stale indexes removes the old global
+	// "todo_title" unique index in databases created before titles were scoped per user.
+	if err := client.Schema.Create(ctx, migrate.WithDropIndex(true)); err != nil {
 		log.Fatalf("failed creating schema resources: %v", err)
 	}
+
+	if ADMIN_EMAILS != "" {
+		if err := promoteAdmins(ctx, client, strings.Split(ADMIN_EMAILS, ",")); err != nil {
+			log.Fatalf("failed promoting administrators: %v", err)
+		}
+	}
+}
+
+// migrateTodoStatuses maps the original "incomplete"/"complete" status enum onto the
+// default workflow states and backfills completed_at, so that the title index, which
+// only covers todos without completed_at, can be created over existing rows.
+func migrateTodoStatuses(ctx context.Context, db *sql.DB) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass('todos') IS NOT NULL").Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range []string{
+		"ALTER TABLE todos ADD COLUMN IF NOT EXISTS completed_at timestamp with time zone NULL",
+		"DROP INDEX IF EXISTS todo_title_user_todos",
+		"UPDATE todos SET status = 'done', completed_at = COALESCE(completed_at, now()) WHERE status = 'complete'",
+		"UPDATE todos SET status = 'todo' WHERE status = 'incomplete'",
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("%s: %w", stmt, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// migrateRefreshTokenSessions drops refresh tokens issued before they were tied to a
+// session. Their "family" can't be mapped onto a session, so those users log in again.
+func migrateRefreshTokenSessions(ctx context.Context, db *sql.DB) error {
+	var exists bool
+	err := db.QueryRowContext(ctx, `SELECT EXISTS (
+		SELECT 1 FROM information_schema.columns
+		WHERE table_name = 'refresh_tokens' AND column_name = 'family'
+	)`).Scan(&exists)
+	if err != nil || !exists {
+		return err
+	}
+	_, err = db.ExecContext(ctx, "DROP TABLE refresh_tokens")
+	return err
+}
+
+// promoteAdmins gives the accounts with the given emails the admin role. Every email
+// must belong to an account, so a typo doesn't go unnoticed.
+func promoteAdmins(ctx context.Context, client *ent.Client, emails []string) error {
+	for i := range emails {
+		emails[i] = strings.TrimSpace(emails[i])
+	}
+	n, err := client.User.Update().
+		Where(user.EmailIn(emails...)).
+		SetRole(auth.RoleAdmin).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n != len(emails) {
+		return fmt.Errorf("only %d of %d emails belong to an account", n, len(emails))
+	}
+	log.Printf("promoted %d users to administrator", n)
+	return nil
 }
@@ -0,0 +1,260 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/accesstoken"
+	"todo/ent/user"
+	"todo/viewer"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// accessTokenPrefix tells personal access tokens apart from JWTs in the Authorization header
+	accessTokenPrefix        = "todo_pat_"
+	defaultAccessTokenExpiry = 30 * 24 * time.Hour
+	maxAccessTokenExpiry     = 365 * 24 * time.Hour
+)
+
+const accessTokenScopesKey contextKey = "accessTokenScopes"
+
+// accessTokenScopes lists the scopes a personal access token can be granted. A
+// resource's "write" scope also allows reading it.
+var accessTokenScopes = []string{
+	"todos:read", "todos:write",
+	"lists:read", "lists:write",
+	"tags:read", "tags:write",
+	"users:read",
+}
+
+// AccessTokenResponse is the JSON representation of a personal access token. Token
+// is only set in the response to its creation.
+type AccessTokenResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Token      string     `json:"token,omitempty"`
+}
+
+func newAccessTokenResponse(t *ent.AccessToken) AccessTokenResponse {
+	return AccessTokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Scopes:     t.Scopes,
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
+
+// hasScope reports whether scopes allow reading, or writing, the resource.
+func hasScope(scopes []string, resource string, write bool) bool {
+	if slices.Contains(scopes, resource+":write") {
+		return true
+	}
+	return !write && slices.Contains(scopes, resource+":read")
+}
+
+// AcceptAccessTokens authenticates requests bearing a personal access token and
+// hands every other request to jwtMiddleware.
+func (handler *Handler) AcceptAccessTokens(jwtMiddleware func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		jwtNext := jwtMiddleware(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || !strings.HasPrefix(bearer, accessTokenPrefix) {
+				jwtNext.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			token, err := handler.Client.AccessToken.Query().
+				Where(
+					accesstoken.TokenHash(auth.HashToken(bearer)),
+					accesstoken.RevokedAtIsNil(),
+					accesstoken.ExpiresAtGT(time.Now()),
+				).
+				WithUser().
+				// There is no viewer before the token is authenticated
+				Only(viewer.SystemContext(ctx))
+			if ent.IsNotFound(err) || (err == nil && token.Edges.User.DisabledAt != nil) {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			if now := time.Now(); token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > lastSeenInterval {
+				if err := token.Update().SetLastUsedAt(now).Exec(ctx); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+
+			ctx = context.WithValue(ctx, userIDKey, token.Edges.User.ID)
+			ctx = context.WithValue(ctx, accessTokenScopesKey, token.Scopes)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireScope limits requests made with a personal access token to the ones its
+// scopes allow. GET and HEAD requests need the resource's read scope, anything else
+// its write scope. Requests from a login session are always allowed.
+func RequireScope(resource string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, ok := r.Context().Value(accessTokenScopesKey).([]string)
+			write := r.Method != http.MethodGet && r.Method != http.MethodHead
+			if ok && !hasScope(scopes, resource, write) {
+				http.Error(w, "access token is missing a required scope", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession rejects requests made with a personal access token, for routes
+// managing the account itself such as sessions, two-factor settings and tokens.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(sessionIDKey).(int); !ok {
+			http.Error(w, "this route requires a login session", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CreateAccessToken creates a personal access token. The token itself is only
+// returned by this request.
+func (handler *Handler) CreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	var tokenDetails struct {
+		Name      string     `json:"name"`
+		Scopes    []string   `json:"scopes"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&tokenDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(tokenDetails.Scopes) == 0 {
+		http.Error(w, "at least one scope is required", http.StatusBadRequest)
+		return
+	}
+	for _, scope := range tokenDetails.Scopes {
+		if !slices.Contains(accessTokenScopes, scope) {
+			http.Error(w, fmt.Sprintf("unknown scope %q", scope), http.StatusBadRequest)
+			return
+		}
+	}
+	now := time.Now()
+	expiresAt := now.Add(defaultAccessTokenExpiry)
+	if tokenDetails.ExpiresAt != nil {
+		expiresAt = *tokenDetails.ExpiresAt
+	}
+	if !expiresAt.After(now) || expiresAt.Sub(now) > maxAccessTokenExpiry {
+		http.Error(w, fmt.Sprintf("expires_at must be in the future and within %v", maxAccessTokenExpiry), http.StatusBadRequest)
+		return
+	}
+
+	secret, err := auth.GenerateToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	token := accessTokenPrefix + secret
+	created, err := handler.Client.AccessToken.Create().
+		SetName(tokenDetails.Name).
+		SetTokenHash(auth.HashToken(token)).
+		SetScopes(tokenDetails.Scopes).
+		SetExpiresAt(expiresAt).
+		SetUserID(userID).
+		Save(ctx)
+	if ent.IsValidationError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := newAccessTokenResponse(created)
+	response.Token = token
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetAccessTokens lists the user's usable personal access tokens.
+func (handler *Handler) GetAccessTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	tokens, err := handler.Client.AccessToken.Query().
+		Where(
+			accesstoken.HasUserWith(user.ID(userID)),
+			accesstoken.RevokedAtIsNil(),
+			accesstoken.ExpiresAtGT(time.Now()),
+		).
+		Order(ent.Asc(accesstoken.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]AccessTokenResponse, len(tokens))
+	for i, t := range tokens {
+		responses[i] = newAccessTokenResponse(t)
+	}
+	json.NewEncoder(w).Encode(responses)
+}
+
+// DeleteAccessToken revokes one of the user's personal access tokens.
+func (handler *Handler) DeleteAccessToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+	tokenID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid token ID", http.StatusBadRequest)
+		return
+	}
+
+	n, err := handler.Client.AccessToken.Update().
+		Where(
+			accesstoken.ID(tokenID),
+			accesstoken.HasUserWith(user.ID(userID)),
+			accesstoken.RevokedAtIsNil(),
+		).
+		SetRevokedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "Token not found or does not belong to user", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"message": "Token revoked"})
+}
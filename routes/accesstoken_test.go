@@ -0,0 +1,29 @@
+package routes
+
+import "testing"
+
+// TestHasScope tests that write scopes imply read access but not the other way round
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []string
+		resource string
+		write    bool
+		want     bool
+	}{
+		{"read with read scope", []string{"todos:read"}, "todos", false, true},
+		{"write with read scope", []string{"todos:read"}, "todos", true, false},
+		{"read with write scope", []string{"todos:write"}, "todos", false, true},
+		{"write with write scope", []string{"todos:write"}, "todos", true, true},
+		{"other resource", []string{"lists:write"}, "todos", false, false},
+		{"no scopes", nil, "todos", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasScope(tt.scopes, tt.resource, tt.write); got != tt.want {
+				t.Errorf("hasScope(%v, %q, %v) = %v, want %v", tt.scopes, tt.resource, tt.write, got, tt.want)
+			}
+		})
+	}
+}
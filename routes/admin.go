@@ -0,0 +1,288 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/accesstoken"
+	"todo/ent/privacy"
+	"todo/ent/session"
+	"todo/ent/todo"
+	"todo/ent/user"
+	"todo/viewer"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const roleKey contextKey = "role"
+
+const (
+	defaultUserPageSize = 50
+	maxUserPageSize     = 200
+)
+
+func (handler *Handler) roles() auth.Roles {
+	if handler.Roles == nil {
+		return auth.DefaultRoles
+	}
+	return handler.Roles
+}
+
+// RequirePermission only lets users whose role grants the permission through. It runs
+// after ViewerMiddleware, which puts the user's current role in the context.
+func (handler *Handler) RequirePermission(permission auth.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, _ := r.Context().Value(roleKey).(string)
+			if !handler.roles().Allows(role, permission) {
+				http.Error(w, "missing required permission", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AdminUser is what administrators see about an account.
+type AdminUser struct {
+	PrivateUser
+	Role        string     `json:"role"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	DisabledAt  *time.Time `json:"disabled_at,omitempty"`
+}
+
+func newAdminUser(u *ent.User) AdminUser {
+	return AdminUser{
+		PrivateUser: newPrivateUser(u),
+		Role:        u.Role,
+		LockedUntil: u.LockedUntil,
+		DisabledAt:  u.DisabledAt,
+	}
+}
+
+// GetAllUsers lists accounts by ID, optionally searching names and emails, e.g.
+// "/admin/users?q=ann&limit=20&offset=40".
+func (handler *Handler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := r.URL.Query()
+
+	limit, offset := defaultUserPageSize, 0
+	var err error
+	if s := params.Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil || limit < 1 || limit > maxUserPageSize {
+			http.Error(w, fmt.Sprintf("Invalid limit, expected 1 to %d", maxUserPageSize), http.StatusBadRequest)
+			return
+		}
+	}
+	if s := params.Get("offset"); s != "" {
+		if offset, err = strconv.Atoi(s); err != nil || offset < 0 {
+			http.Error(w, "Invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+
+	query := handler.Client.User.Query()
+	if q := params.Get("q"); q != "" {
+		query = query.Where(user.Or(user.NameContainsFold(q), user.EmailContainsFold(q)))
+	}
+	if role := params.Get("role"); role != "" {
+		query = query.Where(user.Role(role))
+	}
+	users, err := query.Order(user.ByID()).Limit(limit).Offset(offset).All(ctx)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error fetching users: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]AdminUser, len(users))
+	for i, u := range users {
+		responses[i] = newAdminUser(u)
+	}
+	json.NewEncoder(w).Encode(responses)
+}
+
+// adminTargetUser loads the user named by the {id} URL parameter. On failure it writes
+// the error response and returns nil.
+func (handler *Handler) adminTargetUser(w http.ResponseWriter, r *http.Request) *ent.User {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return nil
+	}
+	u, err := handler.Client.User.Get(r.Context(), userID)
+	if ent.IsNotFound(err) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return nil
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil
+	}
+	return u
+}
+
+// writeAdminError responds to a failed change of another user, with 403 when the
+// privacy rules refused it because the user's role outranks the viewer's.
+func writeAdminError(w http.ResponseWriter, err error) {
+	if errors.Is(err, privacy.Deny) {
+		http.Error(w, "you can't change users whose role grants more than yours", http.StatusForbidden)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// signOutEverywhere ends all of the user's sessions and revokes their personal access tokens.
+func signOutEverywhere(ctx context.Context, client *ent.Client, userID int) error {
+	if err := revokeSessions(ctx, client, session.HasUserWith(user.ID(userID))); err != nil {
+		return err
+	}
+	return client.AccessToken.Update().
+		Where(accesstoken.HasUserWith(user.ID(userID)), accesstoken.RevokedAtIsNil()).
+		SetRevokedAt(time.Now()).
+		Exec(ctx)
+}
+
+// DisableUser blocks an account from logging in and signs it out everywhere.
+func (handler *Handler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	u := handler.adminTargetUser(w, r)
+	if u == nil {
+		return
+	}
+	if u.ID == ctx.Value(userIDKey).(int) {
+		http.Error(w, "you can't disable your own account", http.StatusBadRequest)
+		return
+	}
+
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		if u.DisabledAt == nil {
+			var err error
+			if u, err = tx.User.UpdateOne(u).SetDisabledAt(time.Now()).Save(ctx); err != nil {
+				return err
+			}
+		}
+		return signOutEverywhere(ctx, tx.Client(), u.ID)
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newAdminUser(u))
+}
+
+// EnableUser lets a disabled account log in again.
+func (handler *Handler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	u := handler.adminTargetUser(w, r)
+	if u == nil {
+		return
+	}
+
+	u, err := u.Update().ClearDisabledAt().Save(ctx)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newAdminUser(u))
+}
+
+// UnlockUser lifts a lockout caused by failed logins, for users who can't get to the
+// emailed unlock link.
+func (handler *Handler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	u := handler.adminTargetUser(w, r)
+	if u == nil {
+		return
+	}
+
+	if err := unlockUser(ctx, handler.Client, u.ID); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	u, err := handler.Client.User.Get(ctx, u.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(newAdminUser(u))
+}
+
+// ForcePasswordReset invalidates the user's password, signs them out everywhere and
+// emails them a link to choose a new one.
+func (handler *Handler) ForcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	u := handler.adminTargetUser(w, r)
+	if u == nil {
+		return
+	}
+
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		// An empty hash matches no password
+		if err := tx.User.UpdateOne(u).SetPassword("").Exec(ctx); err != nil {
+			return err
+		}
+		return signOutEverywhere(ctx, tx.Client(), u.ID)
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	if err := handler.sendPasswordReset(ctx, u.Email); err != nil {
+		// The user can still ask for a link with POST /password/forgot
+		log.Println("Failed to send password reset: ", err)
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"message": "Password reset, a link to choose a new one has been sent"})
+}
+
+// SetUserRole assigns a role to another user, e.g. {"role": "admin"}.
+func (handler *Handler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	u := handler.adminTargetUser(w, r)
+	if u == nil {
+		return
+	}
+	// Keeps the last administrator from demoting themselves
+	if u.ID == ctx.Value(userIDKey).(int) {
+		http.Error(w, "you can't change your own role", http.StatusBadRequest)
+		return
+	}
+
+	var roleDetails struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&roleDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, ok := handler.roles()[roleDetails.Role]; !ok {
+		http.Error(w, fmt.Sprintf("unknown role %q", roleDetails.Role), http.StatusBadRequest)
+		return
+	}
+
+	u, err := u.Update().SetRole(roleDetails.Role).Save(ctx)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newAdminUser(u))
+}
+
+// GetUserTodos lists any user's todos, with the same filters and pagination as GetTodos.
+func (handler *Handler) GetUserTodos(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	// Everywhere else the todos of a viewer who may read all of them are still their own
+	r = r.WithContext(viewer.WithAllTodos(r.Context()))
+	handler.writeTodos(w, r, handler.Client.Todo.Query().Where(todo.HasUserWith(user.ID(userID))))
+}
@@ -0,0 +1,265 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	auth "todo/auth"
+	"todo/viewer"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TestRequirePermission tests that routes are only open to roles granting the permission
+func TestRequirePermission(t *testing.T) {
+	handler := newTestHandler(t)
+	handler.Roles = auth.Roles{"user": nil, "admin": auth.Permissions, "support": {auth.PermListUsers}}
+
+	tests := []struct {
+		name       string
+		role       string
+		permission auth.Permission
+		status     int
+	}{
+		{"admin", "admin", auth.PermManageUsers, http.StatusOK},
+		{"custom role with permission", "support", auth.PermListUsers, http.StatusOK},
+		{"custom role without permission", "support", auth.PermManageUsers, http.StatusForbidden},
+		{"regular user", "user", auth.PermListUsers, http.StatusForbidden},
+		{"no role", "", auth.PermListUsers, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+			if tt.role != "" {
+				req = req.WithContext(context.WithValue(req.Context(), roleKey, tt.role))
+			}
+			rec := httptest.NewRecorder()
+			handler.RequirePermission(tt.permission)(ok).ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+// TestDisableUser tests that a disabled user is signed out everywhere and can't log in
+func TestDisableUser(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+
+	hash, err := handler.passwords().Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := handler.Client.User.Create().SetName("admin").SetEmail("admin@example.com").SetPassword(hash).SetRole(auth.RoleAdmin).SaveX(ctx)
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword(hash).SaveX(ctx)
+	s := handler.Client.Session.Create().SetUser(u).SaveX(ctx)
+	pat := handler.Client.AccessToken.Create().SetName("ci").SetTokenHash("pat").SetScopes([]string{"todos:read"}).
+		SetExpiresAt(time.Now().Add(time.Hour)).SetUser(u).SaveX(ctx)
+
+	r := chi.NewRouter()
+	r.Post("/admin/users/{id}/disable", handler.DisableUser)
+	r.With(SystemViewer).Post("/login", handler.Login)
+	adminCtx := context.WithValue(context.Background(), userIDKey, admin.ID)
+	adminCtx = viewer.NewContext(adminCtx, viewer.Viewer{UserID: admin.ID, Permissions: auth.Permissions})
+
+	for _, target := range []int{admin.ID, u.ID} {
+		req := httptest.NewRequest(http.MethodPost, "/admin/users/"+strconv.Itoa(target)+"/disable", nil).WithContext(adminCtx)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		want := http.StatusOK
+		if target == admin.ID {
+			want = http.StatusBadRequest
+		}
+		if rec.Code != want {
+			t.Errorf("disabling user %d: status = %d, want %d", target, rec.Code, want)
+		}
+	}
+
+	if handler.Client.User.GetX(ctx, u.ID).DisabledAt == nil {
+		t.Error("user not disabled")
+	}
+	if handler.Client.Session.GetX(ctx, s.ID).RevokedAt == nil {
+		t.Error("session not revoked")
+	}
+	if handler.Client.AccessToken.GetX(ctx, pat.ID).RevokedAt == nil {
+		t.Error("access token not revoked")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email":"ann@example.com","password":"correct horse"}`))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("login of disabled user: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+// TestViewerRoleFromDatabase tests that permissions follow the stored role and account
+// state rather than what the access token was issued with
+func TestViewerRoleFromDatabase(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	demoted := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	disabled := handler.Client.User.Create().SetName("bob").SetEmail("bob@example.com").SetPassword("hash").
+		SetRole(auth.RoleAdmin).SetDisabledAt(time.Now()).SaveX(ctx)
+	admin := handler.Client.User.Create().SetName("cat").SetEmail("cat@example.com").SetPassword("hash").
+		SetRole(auth.RoleAdmin).SaveX(ctx)
+
+	tests := []struct {
+		name   string
+		userID int
+		status int
+	}{
+		{"demoted user", demoted.ID, http.StatusForbidden},
+		{"disabled admin", disabled.ID, http.StatusUnauthorized},
+		{"admin", admin.ID, http.StatusOK},
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	protected := handler.ViewerMiddleware(handler.RequirePermission(auth.PermListUsers)(ok))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// As if the token still claimed the admin role
+			reqCtx := context.WithValue(context.Background(), userIDKey, tt.userID)
+			reqCtx = context.WithValue(reqCtx, roleKey, auth.RoleAdmin)
+			req := httptest.NewRequest(http.MethodGet, "/admin/users", nil).WithContext(reqCtx)
+			rec := httptest.NewRecorder()
+			protected.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+// TestUnlockUser tests that an administrator can lift a lockout from failed logins
+func TestUnlockUser(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	admin := handler.Client.User.Create().SetName("admin").SetEmail("admin@example.com").SetPassword("hash").SetRole(auth.RoleAdmin).SaveX(ctx)
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").
+		SetFailedLoginCount(lockoutThreshold).SetLockedUntil(time.Now().Add(time.Hour)).SaveX(ctx)
+
+	r := chi.NewRouter()
+	r.Post("/admin/users/{id}/unlock", handler.UnlockUser)
+	adminCtx := context.WithValue(context.Background(), userIDKey, admin.ID)
+	adminCtx = viewer.NewContext(adminCtx, viewer.Viewer{UserID: admin.ID, Permissions: auth.Permissions})
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/"+strconv.Itoa(u.ID)+"/unlock", nil).WithContext(adminCtx)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	u = handler.Client.User.GetX(ctx, u.ID)
+	if isLocked(u) || u.FailedLoginCount != 0 {
+		t.Errorf("user still locked: locked_until %v, %d failed logins", u.LockedUntil, u.FailedLoginCount)
+	}
+}
+
+// TestManagersCantReachHigherRoles tests that a role allowed to manage users can't
+// change accounts whose role grants more than it does, or hand such a role out
+func TestManagersCantReachHigherRoles(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	handler.Roles = auth.Roles{
+		auth.RoleUser:  nil,
+		auth.RoleAdmin: auth.Permissions,
+		"support":      {auth.PermListUsers, auth.PermManageUsers, auth.PermManageRoles},
+	}
+	support := handler.Client.User.Create().SetName("sam").SetEmail("sam@example.com").SetPassword("hash").SetRole("support").SaveX(ctx)
+	admin := handler.Client.User.Create().SetName("admin").SetEmail("admin@example.com").SetPassword("hash").SetRole(auth.RoleAdmin).SaveX(ctx)
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	s := handler.Client.Session.Create().SetUser(admin).SaveX(ctx)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey, support.ID)))
+		})
+	})
+	r.Use(handler.ViewerMiddleware)
+	r.Post("/admin/users/{id}/disable", handler.DisableUser)
+	r.Post("/admin/users/{id}/password-reset", handler.ForcePasswordReset)
+	r.Put("/admin/users/{id}/role", handler.SetUserRole)
+
+	tests := []struct {
+		name   string
+		method string
+		target int
+		action string
+		body   string
+		status int
+	}{
+		{"disable admin", http.MethodPost, admin.ID, "disable", "", http.StatusForbidden},
+		{"reset admin password", http.MethodPost, admin.ID, "password-reset", "", http.StatusForbidden},
+		{"demote admin", http.MethodPut, admin.ID, "role", `{"role":"user"}`, http.StatusForbidden},
+		{"promote to admin", http.MethodPut, u.ID, "role", `{"role":"admin"}`, http.StatusForbidden},
+		{"promote to own role", http.MethodPut, u.ID, "role", `{"role":"support"}`, http.StatusOK},
+		{"disable user", http.MethodPost, u.ID, "disable", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/admin/users/" + strconv.Itoa(tt.target) + "/" + tt.action
+			req := httptest.NewRequest(tt.method, path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+
+	admin = handler.Client.User.GetX(ctx, admin.ID)
+	if admin.DisabledAt != nil || admin.Password == "" || admin.Role != auth.RoleAdmin {
+		t.Errorf("admin changed: %+v", admin)
+	}
+	if handler.Client.Session.GetX(ctx, s.ID).RevokedAt != nil {
+		t.Error("admin session revoked")
+	}
+}
+
+// TestReadAllTodosOnlyOnAdminRoute tests that a viewer who may read every user's todos
+// only gets them from the admin route, and sees just their own everywhere else
+func TestReadAllTodosOnlyOnAdminRoute(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	admin := handler.Client.User.Create().SetName("admin").SetEmail("admin@example.com").SetPassword("hash").SetRole(auth.RoleAdmin).SaveX(ctx)
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	handler.Client.Todo.Create().SetTitle("Admin things").SetUser(admin).SaveX(ctx)
+	handler.Client.Todo.Create().SetTitle("Ann things").SetUser(u).SaveX(ctx)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey, admin.ID)))
+		})
+	})
+	r.Use(handler.ViewerMiddleware)
+	r.Get("/todos", handler.GetTodos)
+	r.Get("/admin/users/{id}/todos", handler.GetUserTodos)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/todos", "Admin things"},
+		{"/admin/users/" + strconv.Itoa(u.ID) + "/todos", "Ann things"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: status = %d: %s", tt.path, rec.Code, rec.Body)
+		}
+		if got := strings.Count(rec.Body.String(), `"title"`); got != 1 || !strings.Contains(rec.Body.String(), tt.want) {
+			t.Errorf("GET %s = %s, want only %q", tt.path, rec.Body, tt.want)
+		}
+	}
+}
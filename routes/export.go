@@ -0,0 +1,491 @@
+package routes
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+	"todo/ent"
+	"todo/ent/accesstoken"
+	"todo/ent/dataexport"
+	"todo/ent/identity"
+	"todo/ent/list"
+	"todo/ent/loginattempt"
+	"todo/ent/session"
+	"todo/ent/tag"
+	"todo/ent/todo"
+	"todo/ent/user"
+	"todo/viewer"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+)
+
+const (
+	// How long a finished export can be downloaded
+	exportTTL = 7 * 24 * time.Hour
+	// How long building an archive may take before the export is marked failed
+	exportTimeout = 10 * time.Minute
+)
+
+// exportIDKey is the claim naming the export a download link is for
+const (
+	exportIDKey     = "exportID"
+	exportTokenType = "export_download"
+)
+
+var errExportPending = errors.New("an export is already being prepared")
+
+// ExportResponse is the JSON representation of a DataExport. DownloadURL is a signed
+// link, valid until the export expires, set once the export is ready.
+type ExportResponse struct {
+	ID          int        `json:"id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	DownloadURL string     `json:"download_url,omitempty"`
+}
+
+func (handler *Handler) newExportResponse(e *ent.DataExport) (ExportResponse, error) {
+	response := ExportResponse{
+		ID:          e.ID,
+		Status:      e.Status.String(),
+		CreatedAt:   e.CreatedAt,
+		CompletedAt: e.CompletedAt,
+		ExpiresAt:   e.ExpiresAt,
+	}
+	if e.Status == dataexport.StatusReady {
+		claims := map[string]interface{}{exportIDKey: e.ID}
+		jwtauth.SetExpiry(claims, e.ExpiresAt)
+		_, token, err := handler.Keys.Encode(exportTokenType, claims)
+		if err != nil {
+			return response, err
+		}
+		response.DownloadURL = fmt.Sprintf("/exports/%d/download?token=%s", e.ID, url.QueryEscape(token))
+	}
+	return response, nil
+}
+
+// CreateExport starts building an archive of all of the user's data in the background.
+func (handler *Handler) CreateExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	// Opportunistically drop archives nobody can download any more
+	if _, err := handler.Client.DataExport.Delete().Where(dataexport.ExpiresAtLT(time.Now())).Exec(ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var export *ent.DataExport
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		pending, err := tx.DataExport.Query().
+			Where(dataexport.HasUserWith(user.ID(userID)), dataexport.StatusEQ(dataexport.StatusPending)).
+			Exist(ctx)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return errExportPending
+		}
+		// The unique index on pending exports catches requests racing past the check
+		export, err = tx.DataExport.Create().
+			SetUserID(userID).
+			SetExpiresAt(time.Now().Add(exportTTL)).
+			Save(ctx)
+		if ent.IsConstraintError(err) {
+			return errExportPending
+		}
+		return err
+	})
+	if errors.Is(err, errExportPending) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	go handler.buildExport(export.ID, userID)
+
+	response, err := handler.newExportResponse(export)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// buildExport builds the archive of a pending export. It outlives the request that
+// started it, so it runs with its own context acting for the user.
+func (handler *Handler) buildExport(exportID, userID int) {
+	ctx := viewer.NewContext(context.Background(), viewer.Viewer{UserID: userID})
+	// Left pending, the export would also stop the user from asking for another one
+	defer func() {
+		if p := recover(); p != nil {
+			handler.finishExport(ctx, exportID, nil, fmt.Errorf("panic: %v\n%s", p, debug.Stack()))
+		}
+	}()
+
+	buildCtx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+	archive, err := exportArchive(buildCtx, handler.Client, userID, time.Now())
+	handler.finishExport(ctx, exportID, archive, err)
+}
+
+// finishExport stores the archive of an export, or marks it failed if building the
+// archive returned an error.
+func (handler *Handler) finishExport(ctx context.Context, exportID int, archive []byte, err error) {
+	update := handler.Client.DataExport.UpdateOneID(exportID).SetCompletedAt(time.Now())
+	if err != nil {
+		log.Println("Failed to build data export: ", err)
+		update.SetStatus(dataexport.StatusFailed)
+	} else {
+		update.SetStatus(dataexport.StatusReady).SetArchive(archive)
+	}
+	// Not found when the user was deleted in the meantime
+	if err := update.Exec(ctx); err != nil && !ent.IsNotFound(err) {
+		log.Println("Failed to store data export: ", err)
+	}
+}
+
+// GetExports lists the user's exports that haven't expired.
+func (handler *Handler) GetExports(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	exports, err := handler.Client.DataExport.Query().
+		Where(dataexport.HasUserWith(user.ID(userID)), dataexport.ExpiresAtGT(time.Now())).
+		Order(ent.Desc(dataexport.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]ExportResponse, len(exports))
+	for i, e := range exports {
+		if responses[i], err = handler.newExportResponse(e); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	json.NewEncoder(w).Encode(responses)
+}
+
+// GetExport returns the status of one of the user's exports, with its download link
+// once it is ready.
+func (handler *Handler) GetExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+	exportID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid export ID", http.StatusBadRequest)
+		return
+	}
+
+	export, err := handler.Client.DataExport.Query().
+		Where(
+			dataexport.ID(exportID),
+			dataexport.HasUserWith(user.ID(userID)),
+			dataexport.ExpiresAtGT(time.Now()),
+		).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		http.Error(w, "Export not found or does not belong to user", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response, err := handler.newExportResponse(export)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// DownloadExport serves an export's archive. It is authorized by the signed token in
+// the link rather than a login, so the link can be opened from anywhere.
+func (handler *Handler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	exportID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid export ID", http.StatusBadRequest)
+		return
+	}
+	token, err := handler.Keys.Verify(r.URL.Query().Get("token"), exportTokenType)
+	if err != nil {
+		http.Error(w, "invalid or expired download link", http.StatusUnauthorized)
+		return
+	}
+	if claimed, ok := token.PrivateClaims()[exportIDKey].(float64); !ok || int(claimed) != exportID {
+		http.Error(w, "invalid or expired download link", http.StatusUnauthorized)
+		return
+	}
+
+	export, err := handler.Client.DataExport.Query().
+		Where(
+			dataexport.ID(exportID),
+			dataexport.StatusEQ(dataexport.StatusReady),
+			dataexport.ExpiresAtGT(time.Now()),
+		).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		http.Error(w, "Export not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="todo-export-%d.zip"`, export.ID))
+	w.Write(export.Archive)
+}
+
+// exportIdentity is the exported form of an Identity.
+type exportIdentity struct {
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// exportLoginAttempt is the exported form of a LoginAttempt.
+type exportLoginAttempt struct {
+	Success   bool      `json:"success"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// exportData is everything stored about a user. Secrets such as password and token
+// hashes are left out.
+type exportData struct {
+	ExportedAt    time.Time             `json:"exported_at"`
+	Profile       PrivateUser           `json:"profile"`
+	Todos         []TodoResponse        `json:"todos"`
+	Lists         []ListResponse        `json:"lists"`
+	Tags          []TagResponse         `json:"tags"`
+	Sessions      []SessionResponse     `json:"sessions"`
+	AccessTokens  []AccessTokenResponse `json:"access_tokens"`
+	Identities    []exportIdentity      `json:"identities"`
+	LoginAttempts []exportLoginAttempt  `json:"login_attempts"`
+}
+
+// exportArchive collects the user's data into a zip archive holding all of it as
+// data.json plus one CSV file per kind of record.
+func exportArchive(ctx context.Context, client *ent.Client, userID int, now time.Time) ([]byte, error) {
+	u, err := client.User.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	owned := user.ID(userID)
+	todos, err := client.Todo.Query().Where(todo.HasUserWith(owned)).Order(todo.ByID()).WithTags().All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := client.List.Query().Where(list.HasOwnerWith(owned)).Order(list.ByID()).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := client.Tag.Query().Where(tag.HasOwnerWith(owned)).Order(tag.ByID()).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := client.Session.Query().Where(session.HasUserWith(owned)).Order(session.ByID()).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := client.AccessToken.Query().Where(accesstoken.HasUserWith(owned)).Order(accesstoken.ByID()).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := client.Identity.Query().Where(identity.HasUserWith(owned)).Order(identity.ByID()).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := client.LoginAttempt.Query().
+		Where(loginattempt.Or(loginattempt.HasUserWith(owned), loginattempt.Email(u.Email))).
+		Order(loginattempt.ByID()).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := exportData{
+		ExportedAt:    now,
+		Profile:       newPrivateUser(u),
+		Todos:         newTodoResponses(todos, now),
+		Lists:         make([]ListResponse, len(lists)),
+		Tags:          make([]TagResponse, len(tags)),
+		Sessions:      make([]SessionResponse, len(sessions)),
+		AccessTokens:  make([]AccessTokenResponse, len(tokens)),
+		Identities:    make([]exportIdentity, len(identities)),
+		LoginAttempts: make([]exportLoginAttempt, len(attempts)),
+	}
+	for i, l := range lists {
+		data.Lists[i] = newListResponse(l)
+	}
+	for i, t := range tags {
+		data.Tags[i] = newTagResponse(t)
+	}
+	for i, s := range sessions {
+		data.Sessions[i] = SessionResponse{ID: s.ID, UserAgent: s.UserAgent, IP: s.IP, CreatedAt: s.CreatedAt, LastSeenAt: s.LastSeenAt}
+	}
+	for i, t := range tokens {
+		data.AccessTokens[i] = newAccessTokenResponse(t)
+	}
+	for i, id := range identities {
+		data.Identities[i] = exportIdentity{id.Issuer, id.Subject, id.Email, id.CreatedAt, id.LastLoginAt}
+	}
+	for i, a := range attempts {
+		data.LoginAttempts[i] = exportLoginAttempt{a.Success, a.IP, a.UserAgent, a.CreatedAt}
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("data.json")
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return nil, err
+	}
+	for _, table := range exportTables(&data) {
+		if err := writeCSV(zw, table.name, table.rows); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportTables flattens the export into CSV tables, the header row first.
+func exportTables(data *exportData) []struct {
+	name string
+	rows [][]string
+} {
+	p := data.Profile
+	tables := []struct {
+		name string
+		rows [][]string
+	}{
+		{"profile.csv", [][]string{
+			{"id", "name", "email", "age", "email_verified", "two_factor_enabled"},
+			{strconv.Itoa(p.ID), p.Name, p.Email, strconv.Itoa(p.Age), strconv.FormatBool(p.EmailVerified), strconv.FormatBool(p.TwoFactorEnabled)},
+		}},
+		{"todos.csv", [][]string{{"id", "title", "status", "priority", "start_at", "due_at", "completed_at", "created_at", "list_id", "parent_id", "recurrence", "tags"}}},
+		{"lists.csv", [][]string{{"id", "name", "created_at"}}},
+		{"tags.csv", [][]string{{"id", "name", "color"}}},
+		{"sessions.csv", [][]string{{"id", "user_agent", "ip", "created_at", "last_seen_at"}}},
+		{"access_tokens.csv", [][]string{{"id", "name", "scopes", "created_at", "expires_at", "last_used_at"}}},
+		{"identities.csv", [][]string{{"issuer", "subject", "email", "created_at", "last_login_at"}}},
+		{"login_attempts.csv", [][]string{{"success", "ip", "user_agent", "created_at"}}},
+	}
+	for _, t := range data.Todos {
+		var tags []string
+		for _, tg := range t.Tags {
+			tags = append(tags, tg.Name)
+		}
+		recurrence := ""
+		if t.Recurrence != nil {
+			recurrence = *t.Recurrence
+		}
+		tables[1].rows = append(tables[1].rows, []string{
+			strconv.Itoa(t.ID), t.Title, t.Status, string(t.Priority), csvTime(t.StartAt), csvTime(t.DueAt),
+			csvTime(t.CompletedAt), csvTime(&t.CreatedAt), csvInt(t.ListID), csvInt(t.ParentID), recurrence, csvJoin(tags),
+		})
+	}
+	for _, l := range data.Lists {
+		tables[2].rows = append(tables[2].rows, []string{strconv.Itoa(l.ID), l.Name, csvTime(&l.CreatedAt)})
+	}
+	for _, t := range data.Tags {
+		tables[3].rows = append(tables[3].rows, []string{strconv.Itoa(t.ID), t.Name, t.Color})
+	}
+	for _, s := range data.Sessions {
+		tables[4].rows = append(tables[4].rows, []string{strconv.Itoa(s.ID), s.UserAgent, s.IP, csvTime(&s.CreatedAt), csvTime(&s.LastSeenAt)})
+	}
+	for _, t := range data.AccessTokens {
+		tables[5].rows = append(tables[5].rows, []string{strconv.Itoa(t.ID), t.Name, csvJoin(t.Scopes), csvTime(&t.CreatedAt), csvTime(&t.ExpiresAt), csvTime(t.LastUsedAt)})
+	}
+	for _, id := range data.Identities {
+		tables[6].rows = append(tables[6].rows, []string{id.Issuer, id.Subject, id.Email, csvTime(&id.CreatedAt), csvTime(&id.LastLoginAt)})
+	}
+	for _, a := range data.LoginAttempts {
+		tables[7].rows = append(tables[7].rows, []string{strconv.FormatBool(a.Success), a.IP, a.UserAgent, csvTime(&a.CreatedAt)})
+	}
+	return tables
+}
+
+func writeCSV(zw *zip.Writer, name string, rows [][]string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(f)
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = csvCell(cell)
+		}
+		cw.Write(cells)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell keeps spreadsheets from running a value as a formula, such as a todo titled
+// "=HYPERLINK(...)", by prefixing the characters that start one with a quote.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func csvInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func csvJoin(values []string) string {
+	var buf bytes.Buffer
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteByte(';')
+		}
+		buf.WriteString(v)
+	}
+	return buf.String()
+}
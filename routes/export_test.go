@@ -0,0 +1,191 @@
+package routes
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"todo/ent"
+	"todo/ent/dataexport"
+	"todo/viewer"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TestExportArchive tests that the archive holds the user's data as JSON and CSV,
+// without secrets and without other users' data
+func TestExportArchive(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	client := newTestHandler(t).Client
+
+	ann := client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("secret-hash").SaveX(ctx)
+	bob := client.User.Create().SetName("bob").SetEmail("bob@example.com").SetPassword("hash").SaveX(ctx)
+	l := client.List.Create().SetName("Work").SetOwner(ann).SaveX(ctx)
+	tg := client.Tag.Create().SetName("urgent").SetOwner(ann).SaveX(ctx)
+	client.Todo.Create().SetTitle("Ship, \"it\"").SetUser(ann).SetList(l).AddTags(tg).SaveX(ctx)
+	client.Todo.Create().SetTitle("Not mine").SetUser(bob).SaveX(ctx)
+	client.Session.Create().SetUser(ann).SetIP("203.0.113.7").SaveX(ctx)
+	client.AccessToken.Create().SetName("ci").SetTokenHash("pat-hash").SetScopes([]string{"todos:read"}).SetExpiresAt(time.Now()).SetUser(ann).SaveX(ctx)
+	client.LoginAttempt.Create().SetEmail(ann.Email).SetSuccess(false).SaveX(ctx)
+
+	annCtx := viewer.NewContext(context.Background(), viewer.Viewer{UserID: ann.ID})
+	archive, err := exportArchive(annCtx, client, ann.ID, time.Now())
+	if err != nil {
+		t.Fatalf("exportArchive error: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+
+	var data exportData
+	if err := json.Unmarshal(files["data.json"], &data); err != nil {
+		t.Fatalf("invalid data.json: %v", err)
+	}
+	if data.Profile.Email != ann.Email {
+		t.Errorf("profile email = %q, want %q", data.Profile.Email, ann.Email)
+	}
+	if len(data.Todos) != 1 || len(data.Todos[0].Tags) != 1 {
+		t.Errorf("todos = %+v, want one todo with one tag", data.Todos)
+	}
+	if len(data.Lists) != 1 || len(data.Tags) != 1 || len(data.Sessions) != 1 || len(data.AccessTokens) != 1 || len(data.LoginAttempts) != 1 {
+		t.Errorf("unexpected record counts in %+v", data)
+	}
+
+	for name, content := range files {
+		for _, secret := range []string{"secret-hash", "pat-hash", "Not mine"} {
+			if strings.Contains(string(content), secret) {
+				t.Errorf("%s contains %q", name, secret)
+			}
+		}
+	}
+
+	tests := []struct {
+		file string
+		rows int
+	}{
+		{"profile.csv", 2},
+		{"todos.csv", 2},
+		{"lists.csv", 2},
+		{"tags.csv", 2},
+		{"sessions.csv", 2},
+		{"access_tokens.csv", 2},
+		{"identities.csv", 1},
+		{"login_attempts.csv", 2},
+	}
+	for _, tt := range tests {
+		rows, err := csv.NewReader(bytes.NewReader(files[tt.file])).ReadAll()
+		if err != nil {
+			t.Errorf("%s: invalid CSV: %v", tt.file, err)
+			continue
+		}
+		if len(rows) != tt.rows {
+			t.Errorf("%s has %d rows, want %d", tt.file, len(rows), tt.rows)
+		}
+	}
+}
+
+// TestDownloadExport tests that an export can only be downloaded with a valid link
+func TestDownloadExport(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	ready := handler.Client.DataExport.Create().SetUser(u).SetStatus(dataexport.StatusReady).
+		SetArchive([]byte("zip")).SetExpiresAt(time.Now().Add(time.Hour)).SaveX(ctx)
+	other := handler.Client.DataExport.Create().SetUser(u).SetStatus(dataexport.StatusReady).
+		SetArchive([]byte("zip")).SetExpiresAt(time.Now().Add(time.Hour)).SaveX(ctx)
+
+	response, err := handler.newExportResponse(ready)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := strings.SplitN(response.DownloadURL, "token=", 2)[1]
+
+	tests := []struct {
+		name   string
+		id     int
+		token  string
+		status int
+	}{
+		{"valid link", ready.ID, token, http.StatusOK},
+		{"other export", other.ID, token, http.StatusUnauthorized},
+		{"missing token", ready.ID, "", http.StatusUnauthorized},
+		{"tampered token", ready.ID, token + "x", http.StatusUnauthorized},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/exports/{id}/download", handler.DownloadExport)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/exports/"+strconv.Itoa(tt.id)+"/download?token="+tt.token, nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+// TestCSVCell tests that values a spreadsheet would run as a formula are defused
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Ship it", "Ship it"},
+		{"", ""},
+		{`=HYPERLINK("https://evil.example.com")`, `'=HYPERLINK("https://evil.example.com")`},
+		{"+1+1", "'+1+1"},
+		{"-1+1", "'-1+1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=1", "a=1"},
+	}
+	for _, tt := range tests {
+		if got := csvCell(tt.value); got != tt.want {
+			t.Errorf("csvCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// TestOnePendingExport tests that a user can't have two exports being prepared, even
+// when both are created at once past the handler's check
+func TestOnePendingExport(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	handler.Client.DataExport.Create().SetUser(u).SetStatus(dataexport.StatusReady).SetExpiresAt(time.Now().Add(time.Hour)).SaveX(ctx)
+	handler.Client.DataExport.Create().SetUser(u).SetExpiresAt(time.Now().Add(time.Hour)).SaveX(ctx)
+
+	err := handler.Client.DataExport.Create().SetUser(u).SetExpiresAt(time.Now().Add(time.Hour)).Exec(ctx)
+	if !ent.IsConstraintError(err) {
+		t.Errorf("second pending export: error = %v, want a constraint error", err)
+	}
+
+	reqCtx := context.WithValue(viewer.NewContext(context.Background(), viewer.Viewer{UserID: u.ID}), userIDKey, u.ID)
+	rec := httptest.NewRecorder()
+	handler.CreateExport(rec, httptest.NewRequest(http.MethodPost, "/exports", nil).WithContext(reqCtx))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
@@ -0,0 +1,204 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"todo/ent"
+	"todo/ent/list"
+	"todo/ent/todo"
+	"todo/ent/user"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (handler *Handler) CreateList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	// Get the userID from the context
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var listDetails struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&listDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	newList, err := handler.Client.List.Create().
+		SetName(listDetails.Name).
+		SetOwnerID(userID).
+		Save(ctx)
+	if ent.IsValidationError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ent.IsConstraintError(err) {
+		http.Error(w, "A list with this name already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newListResponse(newList))
+}
+
+func (handler *Handler) GetLists(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	// Get the userID from the context
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	lists, err := handler.Client.List.Query().
+		Where(list.HasOwnerWith(user.ID(userID))).
+		Order(list.ByName()).
+		All(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]ListResponse, len(lists))
+	for i, l := range lists {
+		responses[i] = newListResponse(l)
+	}
+	json.NewEncoder(w).Encode(responses)
+}
+
+func (handler *Handler) GetList(w http.ResponseWriter, r *http.Request) {
+	listItem, ok := handler.userList(w, r)
+	if !ok {
+		return
+	}
+
+	json.NewEncoder(w).Encode(newListResponse(listItem))
+}
+
+func (handler *Handler) UpdateList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	listItem, ok := handler.userList(w, r)
+	if !ok {
+		return
+	}
+
+	var listDetails struct {
+		Name *string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&listDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updatedList, err := listItem.Update().
+		SetNillableName(listDetails.Name).
+		Save(ctx)
+	if ent.IsValidationError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ent.IsConstraintError(err) {
+		http.Error(w, "A list with this name already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newListResponse(updatedList))
+}
+
+func (handler *Handler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	listItem, ok := handler.userList(w, r)
+	if !ok {
+		return
+	}
+
+	// The todos of the list are kept and simply no longer belong to any list
+	if err := handler.Client.List.DeleteOne(listItem).Exec(ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "List deleted"})
+}
+
+func (handler *Handler) GetListTodos(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	listItem, ok := handler.userList(w, r)
+	if !ok {
+		return
+	}
+
+	userID := ctx.Value(userIDKey).(int)
+	query := handler.Client.Todo.Query().Where(todo.ListID(listItem.ID), todo.HasUserWith(user.ID(userID)))
+	handler.writeTodos(w, r, query)
+}
+
+// userList loads the List named by the {id} URL parameter and ensures it belongs
+// to the user making the request. On failure it writes the error response and returns false.
+func (handler *Handler) userList(w http.ResponseWriter, r *http.Request) (*ent.List, bool) {
+	ctx := r.Context()
+
+	listID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid list ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	// Get the userID from the context to ensure the List belongs to the user making the request
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	listItem, err := handler.Client.List.
+		Query().
+		Where(list.ID(listID), list.HasOwnerWith(user.ID(userID))).
+		Only(ctx)
+	if err != nil {
+		http.Error(w, "List not found or does not belong to user", http.StatusNotFound)
+		return nil, false
+	}
+
+	return listItem, true
+}
+
+// checkListOwner ensures the List with the given ID belongs to the user making the
+// request before a Todo is placed in it. On failure it writes the error response and returns false.
+func (handler *Handler) checkListOwner(w http.ResponseWriter, r *http.Request, listID int) bool {
+	ctx := r.Context()
+
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return false
+	}
+
+	exists, err := handler.Client.List.
+		Query().
+		Where(list.ID(listID), list.HasOwnerWith(user.ID(userID))).
+		Exist(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if !exists {
+		http.Error(w, "List not found or does not belong to user", http.StatusNotFound)
+		return false
+	}
+
+	return true
+}
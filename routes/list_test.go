@@ -0,0 +1,103 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"todo/viewer"
+)
+
+// TestListLifecycle tests list CRUD, moving todos between lists, and that other
+// users' lists can't be read, changed or filled
+func TestListLifecycle(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	ann := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	bob := handler.Client.User.Create().SetName("bob").SetEmail("bob@example.com").SetPassword("hash").SaveX(ctx)
+	bobList := handler.Client.List.Create().SetName("Work").SetOwner(bob).SaveX(ctx)
+
+	r := newUserRouter(handler, ann.ID)
+	r.Post("/lists", handler.CreateList)
+	r.Get("/lists", handler.GetLists)
+	r.Get("/lists/{id}", handler.GetList)
+	r.Patch("/lists/{id}", handler.UpdateList)
+	r.Delete("/lists/{id}", handler.DeleteList)
+	r.Get("/lists/{id}/todos", handler.GetListTodos)
+	r.Post("/todos", handler.CreateTodo)
+	r.Get("/todos/{id}", handler.GetTodo)
+	r.Patch("/todos/{id}", handler.UpdateTodo)
+
+	rec := serve(r, http.MethodPost, "/lists", `{"name":"Work"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create: status = %d: %s", rec.Code, rec.Body)
+	}
+	work := decode[ListResponse](t, rec)
+	home := decode[ListResponse](t, serve(r, http.MethodPost, "/lists", `{"name":"Home"}`))
+	if rec := serve(r, http.MethodPost, "/lists", `{"name":"Work"}`); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate name: status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	workPath, homePath := "/lists/"+strconv.Itoa(work.ID), "/lists/"+strconv.Itoa(home.ID)
+
+	if lists := decode[[]ListResponse](t, serve(r, http.MethodGet, "/lists", "")); len(lists) != 2 || lists[0].Name != "Home" {
+		t.Errorf("lists = %+v, want Home and Work", lists)
+	}
+	if rec := serve(r, http.MethodPatch, homePath, `{"name":"Work"}`); rec.Code != http.StatusConflict {
+		t.Errorf("renaming to a duplicate: status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if got := decode[ListResponse](t, serve(r, http.MethodPatch, homePath, `{"name":"House"}`)); got.Name != "House" {
+		t.Errorf("renamed = %+v", got)
+	}
+
+	rec = serve(r, http.MethodPost, "/todos", `{"title":"Ship it","list_id":`+strconv.Itoa(work.ID)+`}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create todo: status = %d: %s", rec.Code, rec.Body)
+	}
+	todoPath := "/todos/" + strconv.Itoa(decode[TodoResponse](t, rec).ID)
+	if page := decode[todoListing](t, serve(r, http.MethodGet, workPath+"/todos", "")); len(page.Todos) != 1 {
+		t.Errorf("work todos = %+v, want 1", page.Todos)
+	}
+
+	if rec := serve(r, http.MethodPatch, todoPath, `{"list_id":`+strconv.Itoa(home.ID)+`}`); rec.Code != http.StatusOK {
+		t.Fatalf("move todo: status = %d: %s", rec.Code, rec.Body)
+	}
+	if page := decode[todoListing](t, serve(r, http.MethodGet, workPath+"/todos", "")); len(page.Todos) != 0 {
+		t.Errorf("work todos after move = %+v, want none", page.Todos)
+	}
+	if page := decode[todoListing](t, serve(r, http.MethodGet, homePath+"/todos", "")); len(page.Todos) != 1 {
+		t.Errorf("home todos after move = %+v, want 1", page.Todos)
+	}
+
+	// Deleting a list keeps its todos
+	if rec := serve(r, http.MethodDelete, homePath, ""); rec.Code != http.StatusOK {
+		t.Fatalf("delete: status = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(r, http.MethodGet, homePath, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("get deleted list: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if got := decode[TodoResponse](t, serve(r, http.MethodGet, todoPath, "")); got.ListID != nil {
+		t.Errorf("todo of deleted list = %+v, want no list", got)
+	}
+
+	other := "/lists/" + strconv.Itoa(bobList.ID)
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, other, ""},
+		{http.MethodGet, other + "/todos", ""},
+		{http.MethodPatch, other, `{"name":"Mine now"}`},
+		{http.MethodDelete, other, ""},
+		{http.MethodPost, "/todos", `{"title":"Sneaky","list_id":` + strconv.Itoa(bobList.ID) + `}`},
+		{http.MethodPatch, todoPath, `{"list_id":` + strconv.Itoa(bobList.ID) + `}`},
+	}
+	for _, tt := range tests {
+		if rec := serve(r, tt.method, tt.path, tt.body); rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s with another user's list: status = %d, want %d", tt.method, tt.path, rec.Code, http.StatusNotFound)
+		}
+	}
+	if got := handler.Client.List.GetX(ctx, bobList.ID); got.Name != "Work" {
+		t.Errorf("other user's list changed: %+v", got)
+	}
+}
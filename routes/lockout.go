@@ -0,0 +1,173 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/unlocktoken"
+	"todo/mailer"
+)
+
+const (
+	// Consecutive failed logins after which an account is locked
+	lockoutThreshold = 5
+	// The first lock lasts lockoutBase and every further failure doubles it
+	lockoutBase = time.Minute
+	maxLockout  = 24 * time.Hour
+	unlockTTL   = 24 * time.Hour
+)
+
+// lockoutDuration returns how long an account stays locked after the given number of
+// consecutive failed logins.
+func lockoutDuration(failures int) time.Duration {
+	if failures < lockoutThreshold {
+		return 0
+	}
+	lock := lockoutBase
+	for i := lockoutThreshold; i < failures; i++ {
+		lock *= 2
+		if lock >= maxLockout {
+			return maxLockout
+		}
+	}
+	return lock
+}
+
+// clientIP returns the address the request came from, without its port.
+func clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+// recordLoginAttempt logs a login attempt. u is nil when no user has the email.
+func (handler *Handler) recordLoginAttempt(ctx context.Context, r *http.Request, email string, u *ent.User, success bool) {
+	create := handler.Client.LoginAttempt.Create().
+		SetEmail(email).
+		SetSuccess(success).
+		SetIP(clientIP(r)).
+		SetUserAgent(r.UserAgent())
+	if u != nil {
+		create.SetUser(u)
+	}
+	if err := create.Exec(ctx); err != nil {
+		log.Println("Failed to record login attempt: ", err)
+	}
+}
+
+// loginFailed records a failed password or two-factor code. Once the user reaches
+// lockoutThreshold failures their account is locked and they are emailed an unlock link.
+func (handler *Handler) loginFailed(ctx context.Context, r *http.Request, u *ent.User) error {
+	handler.recordLoginAttempt(ctx, r, u.Email, u, false)
+
+	// Incremented in the database, so concurrent attempts are all counted
+	u, err := u.Update().AddFailedLoginCount(1).Save(ctx)
+	if err != nil {
+		return err
+	}
+	lock := lockoutDuration(u.FailedLoginCount)
+	if lock == 0 {
+		return nil
+	}
+	if err := u.Update().SetLockedUntil(time.Now().Add(lock)).Exec(ctx); err != nil {
+		return err
+	}
+	if u.FailedLoginCount == lockoutThreshold {
+		sendInBackground("unlock email", func(ctx context.Context) error {
+			return handler.sendUnlockEmail(ctx, u)
+		})
+	}
+	return nil
+}
+
+// loginSucceeded records a completed login and clears the user's failed logins.
+func (handler *Handler) loginSucceeded(ctx context.Context, r *http.Request, u *ent.User) error {
+	handler.recordLoginAttempt(ctx, r, u.Email, u, true)
+	if u.FailedLoginCount == 0 && u.LockedUntil == nil {
+		return nil
+	}
+	return unlockUser(ctx, handler.Client, u.ID)
+}
+
+// isLocked reports whether the user's account is locked at the moment.
+func isLocked(u *ent.User) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(time.Now())
+}
+
+// unlockUser clears the user's failed logins and lock.
+func unlockUser(ctx context.Context, client *ent.Client, userID int) error {
+	return client.User.UpdateOneID(userID).
+		SetFailedLoginCount(0).
+		ClearLockedUntil().
+		Exec(ctx)
+}
+
+func (handler *Handler) sendUnlockEmail(ctx context.Context, u *ent.User) error {
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return err
+	}
+	err = handler.Client.UnlockToken.Create().
+		SetTokenHash(auth.HashToken(token)).
+		SetExpiresAt(time.Now().Add(unlockTTL)).
+		SetUser(u).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/unlock?token=%s", handler.AppURL, url.QueryEscape(token))
+	return handler.Mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Your account has been locked",
+		Body: fmt.Sprintf("Hi %s,\n\nWe locked your account after %d failed login attempts in a row. "+
+			"If that was you, open the link below to unlock it. It expires in %v.\n\n%s\n\n"+
+			"If it wasn't you, someone may be guessing your password. Consider resetting it.\n",
+			u.Name, lockoutThreshold, unlockTTL, link),
+	})
+}
+
+// UnlockAccount unlocks an account using a token from a lockout email.
+func (handler *Handler) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token := r.URL.Query().Get("token")
+
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		unlock, err := tx.UnlockToken.Query().
+			Where(
+				unlocktoken.TokenHash(auth.HashToken(token)),
+				unlocktoken.UsedAtIsNil(),
+				unlocktoken.ExpiresAtGT(time.Now()),
+			).
+			WithUser().
+			Only(ctx)
+		if err != nil {
+			return err
+		}
+		if err := unlock.Update().SetUsedAt(time.Now()).Exec(ctx); err != nil {
+			return err
+		}
+		return unlockUser(ctx, tx.Client(), unlock.Edges.User.ID)
+	})
+	if ent.IsNotFound(err) {
+		http.Error(w, "invalid or expired unlock token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to unlock account", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Account unlocked",
+	})
+}
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo/viewer"
+)
+
+// TestLockoutDuration tests the exponential backoff of locked accounts
+func TestLockoutDuration(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{lockoutThreshold - 1, 0},
+		{lockoutThreshold, time.Minute},
+		{lockoutThreshold + 1, 2 * time.Minute},
+		{lockoutThreshold + 3, 8 * time.Minute},
+		{lockoutThreshold + 20, maxLockout},
+	}
+
+	for _, tt := range tests {
+		if got := lockoutDuration(tt.failures); got != tt.want {
+			t.Errorf("lockoutDuration(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+// TestLockoutSendsUnlockEmail tests that the login reaching the threshold locks the
+// account and that the unlock link is emailed without holding up that login
+func TestLockoutSendsUnlockEmail(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	sent := make(chanMailer)
+	handler.Mailer = sent
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	for i := 0; i < lockoutThreshold; i++ {
+		// Nobody reads the email yet, so this would block if it were sent in line
+		if err := handler.loginFailed(ctx, req, handler.Client.User.GetX(ctx, u.ID)); err != nil {
+			t.Fatalf("loginFailed error: %v", err)
+		}
+	}
+	if !isLocked(handler.Client.User.GetX(ctx, u.ID)) {
+		t.Error("account not locked")
+	}
+	select {
+	case msg := <-sent:
+		if msg.To != u.Email {
+			t.Errorf("unlock email sent to %s, want %s", msg.To, u.Email)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no unlock email sent")
+	}
+}
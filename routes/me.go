@@ -0,0 +1,274 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+	"todo/ent"
+	"todo/ent/accesstoken"
+	"todo/ent/dataexport"
+	"todo/ent/emailverificationtoken"
+	"todo/ent/identity"
+	"todo/ent/list"
+	"todo/ent/loginattempt"
+	"todo/ent/passwordresettoken"
+	"todo/ent/recoverycode"
+	"todo/ent/refreshtoken"
+	"todo/ent/session"
+	"todo/ent/tag"
+	"todo/ent/todo"
+	"todo/ent/unlocktoken"
+	"todo/ent/user"
+)
+
+// freshLoginWindow is how recently users without a password must have logged in to
+// make changes that would otherwise need their password.
+const freshLoginWindow = 10 * time.Minute
+
+// confirmIdentity checks the current password of a user making a sensitive change to
+// their account. Users without a password, such as ones who only sign in through
+// OpenID Connect or whose password an administrator reset, must have started the
+// current session recently instead. On failure it writes the error response and
+// returns false.
+func (handler *Handler) confirmIdentity(w http.ResponseWriter, r *http.Request, u *ent.User, password string) bool {
+	if u.Password != "" {
+		if !handler.passwords().Check(password, u.Password) {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return false
+		}
+		return true
+	}
+
+	ctx := r.Context()
+	sessionID, _ := ctx.Value(sessionIDKey).(int)
+	current, err := handler.Client.Session.Get(ctx, sessionID)
+	if err != nil && !ent.IsNotFound(err) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if current == nil || time.Since(current.CreatedAt) > freshLoginWindow {
+		http.Error(w, "log in again to confirm this change", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// GetMe returns the logged in user's own account.
+func (handler *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	u, err := handler.Client.User.Get(ctx, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(newPrivateUser(u))
+}
+
+// UpdateMe changes the user's name, age or email. Changing the email needs the
+// current password, and the new address has to be verified again.
+func (handler *Handler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	var updateDetails struct {
+		Name            *string       `json:"name"`
+		Age             nullable[int] `json:"age"`
+		Email           *string       `json:"email"`
+		CurrentPassword string        `json:"current_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&updateDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	u, err := handler.Client.User.Get(ctx, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	update := u.Update()
+	if updateDetails.Name != nil {
+		if *updateDetails.Name == "" {
+			http.Error(w, "name must not be empty", http.StatusBadRequest)
+			return
+		}
+		update.SetName(*updateDetails.Name)
+	}
+	if updateDetails.Age.Set {
+		if updateDetails.Age.Value == nil {
+			update.ClearAge()
+		} else {
+			update.SetAge(*updateDetails.Age.Value)
+		}
+	}
+	emailChanged := updateDetails.Email != nil && *updateDetails.Email != u.Email
+	if emailChanged {
+		if !validEmail(*updateDetails.Email) {
+			http.Error(w, "invalid email address", http.StatusBadRequest)
+			return
+		}
+		// Otherwise a stolen session could move the account to another mailbox and
+		// take it over with a password reset
+		if !handler.confirmIdentity(w, r, u, updateDetails.CurrentPassword) {
+			return
+		}
+		update.SetEmail(*updateDetails.Email).SetEmailVerified(false)
+	}
+
+	updatedUser, err := update.Save(ctx)
+	if ent.IsValidationError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ent.IsConstraintError(err) {
+		http.Error(w, "email or name and age already taken", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if emailChanged {
+		if err := handler.sendVerificationEmail(ctx, updatedUser); err != nil {
+			// The user can ask for another one with POST /verify/resend
+			log.Println("Failed to send verification email: ", err)
+		}
+	}
+
+	json.NewEncoder(w).Encode(newPrivateUser(updatedUser))
+}
+
+// ChangePassword sets a new password given the current one, and logs the user out
+// of every other session. Users without a password need a recent login instead, see
+// confirmIdentity.
+func (handler *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+	sessionID, _ := ctx.Value(sessionIDKey).(int)
+
+	var passwordDetails struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&passwordDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	u, err := handler.Client.User.Get(ctx, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !handler.confirmIdentity(w, r, u, passwordDetails.CurrentPassword) {
+		return
+	}
+	hashedPassword, ok := handler.hashNewPassword(w, passwordDetails.NewPassword)
+	if !ok {
+		return
+	}
+
+	err = withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		if err := tx.User.UpdateOneID(userID).SetPassword(hashedPassword).Exec(ctx); err != nil {
+			return err
+		}
+		return revokeSessions(ctx, tx.Client(), session.HasUserWith(user.ID(userID)), session.IDNEQ(sessionID))
+	})
+	if err != nil {
+		http.Error(w, "Failed to change password", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Password changed successfully",
+	})
+}
+
+// DeleteMe deletes the user's account along with their todos, lists, tags and
+// everything else tied to it. The user has to confirm it's them, see confirmIdentity.
+func (handler *Handler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	var deleteDetails struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&deleteDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	u, err := handler.Client.User.Get(ctx, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !handler.confirmIdentity(w, r, u, deleteDetails.Password) {
+		return
+	}
+
+	err = withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		return deleteUser(ctx, tx, u)
+	})
+	if err != nil {
+		http.Error(w, "Failed to delete account", http.StatusInternalServerError)
+		return
+	}
+
+	clearAuthCookies(w)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Account deleted",
+	})
+}
+
+// deleteUser deletes the user and every row that belongs to them. The foreign keys
+// to users don't cascade, so rows are deleted children first.
+func deleteUser(ctx context.Context, tx *ent.Tx, u *ent.User) error {
+	owned := user.ID(u.ID)
+	deletes := []func() (int, error){
+		func() (int, error) {
+			return tx.RefreshToken.Delete().Where(refreshtoken.HasUserWith(owned)).Exec(ctx)
+		},
+		func() (int, error) { return tx.Session.Delete().Where(session.HasUserWith(owned)).Exec(ctx) },
+		// Tag links are removed by the join table's cascading foreign keys
+		func() (int, error) { return tx.Todo.Delete().Where(todo.HasUserWith(owned)).Exec(ctx) },
+		func() (int, error) { return tx.List.Delete().Where(list.HasOwnerWith(owned)).Exec(ctx) },
+		func() (int, error) { return tx.Tag.Delete().Where(tag.HasOwnerWith(owned)).Exec(ctx) },
+		func() (int, error) {
+			return tx.AccessToken.Delete().Where(accesstoken.HasUserWith(owned)).Exec(ctx)
+		},
+		func() (int, error) {
+			return tx.PasswordResetToken.Delete().Where(passwordresettoken.HasUserWith(owned)).Exec(ctx)
+		},
+		func() (int, error) {
+			return tx.EmailVerificationToken.Delete().Where(emailverificationtoken.HasUserWith(owned)).Exec(ctx)
+		},
+		func() (int, error) {
+			return tx.RecoveryCode.Delete().Where(recoverycode.HasUserWith(owned)).Exec(ctx)
+		},
+		func() (int, error) {
+			return tx.UnlockToken.Delete().Where(unlocktoken.HasUserWith(owned)).Exec(ctx)
+		},
+		func() (int, error) { return tx.Identity.Delete().Where(identity.HasUserWith(owned)).Exec(ctx) },
+		func() (int, error) {
+			return tx.DataExport.Delete().Where(dataexport.HasUserWith(owned)).Exec(ctx)
+		},
+		func() (int, error) {
+			return tx.LoginAttempt.Delete().
+				Where(loginattempt.Or(loginattempt.HasUserWith(owned), loginattempt.Email(u.Email))).
+				Exec(ctx)
+		},
+	}
+	for _, del := range deletes {
+		if _, err := del(); err != nil {
+			return err
+		}
+	}
+	return tx.User.DeleteOneID(u.ID).Exec(ctx)
+}
@@ -0,0 +1,164 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todo/ent"
+	"todo/ent/migrate"
+	"todo/viewer"
+)
+
+// TestDeleteUser tests that deleting a user removes everything tied to them and
+// nothing belonging to other users
+func TestDeleteUser(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	client := newTestHandler(t).Client
+
+	seed := func(name string) *ent.User {
+		u := client.User.Create().SetName(name).SetEmail(name + "@example.com").SetPassword("hash").SaveX(ctx)
+		l := client.List.Create().SetName("Work").SetOwner(u).SaveX(ctx)
+		tg := client.Tag.Create().SetName("urgent").SetOwner(u).SaveX(ctx)
+		parent := client.Todo.Create().SetTitle("Ship it").SetUser(u).SetList(l).AddTags(tg).SaveX(ctx)
+		client.Todo.Create().SetTitle("Write tests").SetUser(u).SetParent(parent).SaveX(ctx)
+		s := client.Session.Create().SetUser(u).SaveX(ctx)
+		client.RefreshToken.Create().SetTokenHash(name + "-refresh").SetExpiresAt(time.Now()).SetUser(u).SetSession(s).SaveX(ctx)
+		client.AccessToken.Create().SetName("ci").SetTokenHash(name + "-pat").SetScopes([]string{"todos:read"}).SetExpiresAt(time.Now()).SetUser(u).SaveX(ctx)
+		client.PasswordResetToken.Create().SetTokenHash(name + "-reset").SetExpiresAt(time.Now()).SetUser(u).SaveX(ctx)
+		client.EmailVerificationToken.Create().SetTokenHash(name + "-verify").SetEmail(u.Email).SetExpiresAt(time.Now()).SetUser(u).SaveX(ctx)
+		client.RecoveryCode.Create().SetCodeHash(name + "-recovery").SetUser(u).SaveX(ctx)
+		client.UnlockToken.Create().SetTokenHash(name + "-unlock").SetExpiresAt(time.Now()).SetUser(u).SaveX(ctx)
+		client.Identity.Create().SetIssuer("https://idp.example.com").SetSubject(name).SetUser(u).SaveX(ctx)
+		client.LoginAttempt.Create().SetEmail(u.Email).SetSuccess(true).SetUser(u).SaveX(ctx)
+		client.DataExport.Create().SetExpiresAt(time.Now()).SetUser(u).SaveX(ctx)
+		return u
+	}
+	ann, bob := seed("ann"), seed("bob")
+
+	// Deleting runs as the user whose account it is
+	annCtx := viewer.NewContext(context.Background(), viewer.Viewer{UserID: ann.ID})
+	err := withTx(annCtx, client, func(tx *ent.Tx) error {
+		return deleteUser(annCtx, tx, ann)
+	})
+	if err != nil {
+		t.Fatalf("deleteUser error: %v", err)
+	}
+
+	if _, err := client.User.Get(ctx, ann.ID); !ent.IsNotFound(err) {
+		t.Errorf("deleted user still found, err = %v", err)
+	}
+	if _, err := client.User.Get(ctx, bob.ID); err != nil {
+		t.Errorf("other user gone: %v", err)
+	}
+	counts := map[string]int{
+		"todos":          client.Todo.Query().CountX(ctx),
+		"lists":          client.List.Query().CountX(ctx),
+		"tags":           client.Tag.Query().CountX(ctx),
+		"sessions":       client.Session.Query().CountX(ctx),
+		"access tokens":  client.AccessToken.Query().CountX(ctx),
+		"identities":     client.Identity.Query().CountX(ctx),
+		"login attempts": client.LoginAttempt.Query().CountX(ctx),
+		"data exports":   client.DataExport.Query().CountX(ctx),
+	}
+	want := map[string]int{"todos": 2, "lists": 1, "tags": 1, "sessions": 1, "access tokens": 1, "identities": 1, "login attempts": 1, "data exports": 1}
+	for name, n := range counts {
+		if n != want[name] {
+			t.Errorf("%d %s left, want %d", n, name, want[name])
+		}
+	}
+}
+
+// TestErasureCoversAllTables fails when a table is added without deciding how
+// deleteUser erases it, so no personal data is left behind by account deletion
+func TestErasureCoversAllTables(t *testing.T) {
+	handled := map[string]bool{
+		"users": true, "todos": true, "tag_todos": true, "lists": true, "tags": true,
+		"sessions": true, "refresh_tokens": true, "access_tokens": true,
+		"password_reset_tokens": true, "email_verification_tokens": true, "recovery_codes": true,
+		"unlock_tokens": true, "identities": true, "login_attempts": true, "data_exports": true,
+		// Holds only the jti and expiry of revoked access tokens
+		"revoked_tokens": true,
+	}
+	for _, table := range migrate.Tables {
+		if !handled[table.Name] {
+			t.Errorf("table %s is not covered by deleteUser", table.Name)
+		}
+	}
+}
+
+// TestConfirmIdentity tests that sensitive account changes need the password, or a
+// recent login from users without one
+func TestConfirmIdentity(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	hash, err := handler.Passwords.Hash("correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ann := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword(hash).SaveX(ctx)
+	bob := handler.Client.User.Create().SetName("bob").SetEmail("bob@example.com").SetPassword("").SaveX(ctx)
+	fresh := handler.Client.Session.Create().SetUser(bob).SaveX(ctx)
+	stale := handler.Client.Session.Create().SetUser(bob).SetCreatedAt(time.Now().Add(-time.Hour)).SaveX(ctx)
+
+	tests := []struct {
+		name      string
+		user      *ent.User
+		sessionID int
+		password  string
+		want      bool
+	}{
+		{"right password", ann, fresh.ID, "correct horse battery", true},
+		{"wrong password", ann, fresh.ID, "wrong", false},
+		{"no password, recent login", bob, fresh.ID, "", true},
+		{"no password, old login", bob, stale.ID, "", false},
+		{"no password, no session", bob, 0, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/me", nil).
+				WithContext(context.WithValue(ctx, sessionIDKey, tt.sessionID))
+			rec := httptest.NewRecorder()
+			if got := handler.confirmIdentity(rec, req, tt.user, tt.password); got != tt.want {
+				t.Errorf("confirmIdentity() = %v, want %v", got, tt.want)
+			}
+			if !tt.want && rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
+
+// TestChangePasswordWithoutPassword tests that users without a password can set one
+// after logging in recently
+func TestChangePasswordWithoutPassword(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("").SaveX(ctx)
+	stale := handler.Client.Session.Create().SetUser(u).SetCreatedAt(time.Now().Add(-time.Hour)).SaveX(ctx)
+	fresh := handler.Client.Session.Create().SetUser(u).SaveX(ctx)
+
+	for _, tt := range []struct {
+		sessionID int
+		status    int
+	}{
+		{stale.ID, http.StatusUnauthorized},
+		{fresh.ID, http.StatusOK},
+	} {
+		reqCtx := context.WithValue(ctx, userIDKey, u.ID)
+		reqCtx = context.WithValue(reqCtx, sessionIDKey, tt.sessionID)
+		body := `{"new_password":"correct horse battery"}`
+		req := httptest.NewRequest(http.MethodPut, "/me/password", strings.NewReader(body)).WithContext(reqCtx)
+		rec := httptest.NewRecorder()
+		handler.ChangePassword(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("session %d: status = %d, want %d: %s", tt.sessionID, rec.Code, tt.status, rec.Body)
+		}
+	}
+
+	if u = handler.Client.User.GetX(ctx, u.ID); !handler.passwords().Check("correct horse battery", u.Password) {
+		t.Error("password not set")
+	}
+}
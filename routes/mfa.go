@@ -0,0 +1,366 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/recoverycode"
+	"todo/ent/user"
+
+	"github.com/go-chi/jwtauth/v5"
+)
+
+const (
+	// Issuer shown next to the account in authenticator apps
+	totpIssuer = "Todo"
+	// How long the password step of a two-step login stays valid
+	mfaTokenTTL       = 5 * time.Minute
+	recoveryCodeCount = 10
+)
+
+// mfaUserIDKey is the claim identifying the user in an "mfa pending" token. Those
+// tokens are of their own type, so they are never accepted as access tokens.
+const (
+	mfaUserIDKey = "mfaUserID"
+	mfaTokenType = "mfa"
+)
+
+var errInvalidSecondFactor = errors.New("invalid two-factor code")
+
+// makeMFAToken mints the short-lived token returned by Login once the password of a
+// user with two-factor authentication has been checked.
+func (handler *Handler) makeMFAToken(u *ent.User) (string, error) {
+	jti, err := auth.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	claims := map[string]interface{}{mfaUserIDKey: u.ID, "jti": jti}
+	jwtauth.SetIssuedNow(claims)
+	jwtauth.SetExpiryIn(claims, mfaTokenTTL)
+	_, tokenString, err := handler.Keys.Encode(mfaTokenType, claims)
+	return tokenString, err
+}
+
+// checkSecondFactor accepts either a current TOTP code or one of the user's unused
+// recovery codes, and consumes it so it can't be used again.
+func checkSecondFactor(ctx context.Context, client *ent.Client, u *ent.User, code, recoveryCode string) error {
+	switch {
+	case code != "" && u.TotpSecret != nil:
+		step, ok := auth.ValidateTOTP(*u.TotpSecret, code, time.Now())
+		if !ok {
+			return errInvalidSecondFactor
+		}
+		// Only moves forward, so a code that was already accepted is refused
+		n, err := client.User.Update().
+			Where(user.ID(u.ID), user.Or(user.TotpLastStepIsNil(), user.TotpLastStepLT(step))).
+			SetTotpLastStep(step).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errInvalidSecondFactor
+		}
+		return nil
+	case recoveryCode != "":
+		n, err := client.RecoveryCode.Update().
+			Where(
+				recoverycode.HasUserWith(user.ID(u.ID)),
+				recoverycode.CodeHash(auth.HashToken(strings.ToLower(strings.TrimSpace(recoveryCode)))),
+				recoverycode.UsedAtIsNil(),
+			).
+			SetUsedAt(time.Now()).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errInvalidSecondFactor
+		}
+		return nil
+	default:
+		return errInvalidSecondFactor
+	}
+}
+
+// replaceRecoveryCodes discards the user's recovery codes and returns a fresh set.
+func replaceRecoveryCodes(ctx context.Context, tx *ent.Tx, u *ent.User) ([]string, error) {
+	if _, err := tx.RecoveryCode.Delete().Where(recoverycode.HasUserWith(user.ID(u.ID))).Exec(ctx); err != nil {
+		return nil, err
+	}
+	codes := make([]string, recoveryCodeCount)
+	builders := make([]*ent.RecoveryCodeCreate, recoveryCodeCount)
+	for i := range codes {
+		code, err := auth.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		builders[i] = tx.RecoveryCode.Create().SetCodeHash(auth.HashToken(code)).SetUser(u)
+	}
+	if err := tx.RecoveryCode.CreateBulk(builders...).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// LoginMFA completes a two-step login, exchanging the token returned by Login and a
+// TOTP or recovery code for a session. The token can only be used once, and failed
+// codes count towards the lockout like failed passwords.
+func (handler *Handler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var mfaDetails struct {
+		MFAToken     string `json:"mfa_token"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&mfaDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	token, err := handler.Keys.Verify(mfaDetails.MFAToken, mfaTokenType)
+	if err != nil {
+		http.Error(w, "invalid or expired mfa token", http.StatusUnauthorized)
+		return
+	}
+	userID, ok := token.PrivateClaims()[mfaUserIDKey].(float64)
+	if !ok || token.JwtID() == "" {
+		http.Error(w, "invalid or expired mfa token", http.StatusUnauthorized)
+		return
+	}
+	// Each token is good for a single code, so guessing takes a password each time.
+	// Used tokens go on the same denylist as revoked access tokens.
+	err = handler.Client.RevokedToken.Create().
+		SetJti(token.JwtID()).
+		SetExpiresAt(token.Expiration()).
+		Exec(ctx)
+	if ent.IsConstraintError(err) {
+		http.Error(w, "invalid or expired mfa token", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	u, err := handler.Client.User.Get(ctx, int(userID))
+	if err != nil || !u.TotpEnabled {
+		http.Error(w, "invalid or expired mfa token", http.StatusUnauthorized)
+		return
+	}
+	// Guessing codes counts towards the same lockout as guessing passwords
+	if isLocked(u) {
+		handler.recordLoginAttempt(ctx, r, u.Email, u, false)
+		http.Error(w, errInvalidSecondFactor.Error(), http.StatusUnauthorized)
+		return
+	}
+	err = checkSecondFactor(ctx, handler.Client, u, mfaDetails.Code, mfaDetails.RecoveryCode)
+	if errors.Is(err, errInvalidSecondFactor) {
+		if err := handler.loginFailed(ctx, r, u); err != nil {
+			log.Println("Failed to record failed login: ", err)
+		}
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if u.DisabledAt != nil {
+		http.Error(w, errAccountDisabled.Error(), http.StatusForbidden)
+		return
+	}
+	if err := handler.loginSucceeded(ctx, r, u); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	accessToken, refreshToken, err := handler.startSession(ctx, r, u)
+	if err != nil {
+		http.Error(w, "Failed to create token", http.StatusInternalServerError)
+		return
+	}
+	setAuthCookies(w, accessToken, refreshToken)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "User logged in successfully",
+	})
+}
+
+// EnrollTOTP starts two-factor enrollment by generating a new secret. It only takes
+// effect once a first code is sent to ConfirmTOTP.
+func (handler *Handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	u, err := handler.Client.User.Get(ctx, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if u.TotpEnabled {
+		http.Error(w, "two-factor authentication already enabled", http.StatusConflict)
+		return
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := u.Update().SetTotpSecret(secret).ClearTotpLastStep().Exec(ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"secret": secret,
+		"uri":    auth.TOTPURI(totpIssuer, u.Email, secret),
+	})
+}
+
+// ConfirmTOTP enables two-factor authentication after checking a first code from
+// the enrolled device, and returns the user's recovery codes. They are only shown once.
+func (handler *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	var confirmDetails struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&confirmDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var codes []string
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		u, err := tx.User.Get(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if u.TotpEnabled || u.TotpSecret == nil {
+			return errInvalidSecondFactor
+		}
+		if err := checkSecondFactor(ctx, tx.Client(), u, confirmDetails.Code, ""); err != nil {
+			return err
+		}
+		if err := tx.User.UpdateOne(u).SetTotpEnabled(true).Exec(ctx); err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(ctx, tx, u)
+		return err
+	})
+	if errors.Is(err, errInvalidSecondFactor) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"recovery_codes": codes,
+	})
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes, given a current TOTP code.
+func (handler *Handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	var regenerateDetails struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&regenerateDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var codes []string
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		u, err := tx.User.Get(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !u.TotpEnabled {
+			return errInvalidSecondFactor
+		}
+		if err := checkSecondFactor(ctx, tx.Client(), u, regenerateDetails.Code, ""); err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(ctx, tx, u)
+		return err
+	})
+	if errors.Is(err, errInvalidSecondFactor) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTOTP turns two-factor authentication off. It asks for the password and a
+// TOTP or recovery code, so a stolen session alone can't remove the second factor.
+func (handler *Handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	var disableDetails struct {
+		Password     string `json:"password"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&disableDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		u, err := tx.User.Get(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !u.TotpEnabled || !handler.passwords().Check(disableDetails.Password, u.Password) {
+			return errInvalidSecondFactor
+		}
+		if err := checkSecondFactor(ctx, tx.Client(), u, disableDetails.Code, disableDetails.RecoveryCode); err != nil {
+			return err
+		}
+		if _, err := tx.RecoveryCode.Delete().Where(recoverycode.HasUserWith(user.ID(u.ID))).Exec(ctx); err != nil {
+			return err
+		}
+		return tx.User.UpdateOne(u).
+			SetTotpEnabled(false).
+			ClearTotpSecret().
+			ClearTotpLastStep().
+			Exec(ctx)
+	})
+	if errors.Is(err, errInvalidSecondFactor) {
+		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Two-factor authentication disabled",
+	})
+}
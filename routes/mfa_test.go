@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	auth "todo/auth"
+	"todo/viewer"
+)
+
+// TestMFATokenSingleUse tests that the token from the password step is good for one
+// code only, whether or not that code was right
+func TestMFATokenSingleUse(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").
+		SetTotpSecret("secret").SetTotpEnabled(true).SaveX(ctx)
+	for _, code := range []string{"first-code", "second-code"} {
+		handler.Client.RecoveryCode.Create().SetCodeHash(auth.HashToken(code)).SetUser(u).SaveX(ctx)
+	}
+
+	loginMFA := func(token, recoveryCode string) int {
+		body := `{"mfa_token":"` + token + `","recovery_code":"` + recoveryCode + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/login/mfa", strings.NewReader(body)).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		handler.LoginMFA(rec, req)
+		return rec.Code
+	}
+	newToken := func() string {
+		token, err := handler.makeMFAToken(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	used := newToken()
+	if status := loginMFA(used, "first-code"); status != http.StatusOK {
+		t.Fatalf("first use: status = %d, want %d", status, http.StatusOK)
+	}
+	if status := loginMFA(used, "second-code"); status != http.StatusUnauthorized {
+		t.Errorf("replayed token: status = %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	guessed := newToken()
+	if status := loginMFA(guessed, "wrong-code"); status != http.StatusUnauthorized {
+		t.Errorf("wrong code: status = %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status := loginMFA(guessed, "second-code"); status != http.StatusUnauthorized {
+		t.Errorf("token after a wrong code: status = %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status := loginMFA(newToken(), "second-code"); status != http.StatusOK {
+		t.Errorf("fresh token: status = %d, want %d", status, http.StatusOK)
+	}
+}
@@ -0,0 +1,260 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/identity"
+	"todo/ent/recoverycode"
+	"todo/ent/user"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-chi/jwtauth/v5"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// oidcFlowCookie holds the state, nonce and PKCE verifier of a login in progress
+	oidcFlowCookie    = "oidc_flow"
+	oidcFlowTokenType = "oidc_flow"
+	oidcFlowTTL       = 10 * time.Minute
+)
+
+var errOIDCFlow = errors.New("invalid or expired login attempt")
+
+// OIDC logs users in through an OpenID Connect provider using the authorization
+// code flow with PKCE.
+type OIDC struct {
+	issuer   string
+	config   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// oidcClaims are the ID token claims used to find or create the user.
+type oidcClaims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// NewOIDC discovers the provider's endpoints and keys from its issuer URL.
+func NewOIDC(ctx context.Context, issuer, clientID, clientSecret, redirectURL string) (*OIDC, error) {
+	provider, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discovering %s: %w", issuer, err)
+	}
+	return &OIDC{
+		issuer: issuer,
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: clientID}),
+	}, nil
+}
+
+// authCodeURL returns the provider URL the user is sent to for logging in.
+func (o *OIDC) authCodeURL(state, nonce, verifier string) string {
+	return o.config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// exchange redeems an authorization code and returns the claims of the verified ID token.
+func (o *OIDC) exchange(ctx context.Context, code, verifier, nonce string) (*oidcClaims, error) {
+	token, err := o.config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("no id_token in token response")
+	}
+	idToken, err := o.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// OIDCLogin redirects the user to the OpenID Connect provider to log in.
+func (handler *Handler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	state, err := auth.GenerateToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	nonce, err := auth.GenerateToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	// The flow is kept in a signed cookie, so the callback can't be handed a forged verifier
+	claims := map[string]interface{}{"oidcState": state, "oidcNonce": nonce, "oidcVerifier": verifier}
+	jwtauth.SetExpiryIn(claims, oidcFlowTTL)
+	_, flow, err := handler.Keys.Encode(oidcFlowTokenType, claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		HttpOnly: true,
+		MaxAge:   int(oidcFlowTTL.Seconds()),
+		// Lax so the cookie comes back on the provider's redirect
+		SameSite: http.SameSiteLaxMode,
+		// Uncomment below for HTTPS:
+		// Secure: true,
+		Path:  "/oidc",
+		Name:  oidcFlowCookie,
+		Value: flow,
+	})
+
+	http.Redirect(w, r, handler.OIDC.authCodeURL(state, nonce, verifier), http.StatusFound)
+}
+
+// OIDCCallback completes a login at the OpenID Connect provider, then logs the user
+// in like Login does.
+func (handler *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		http.Error(w, fmt.Sprintf("login failed: %s", providerErr), http.StatusUnauthorized)
+		return
+	}
+
+	state, nonce, verifier, err := handler.oidcFlow(r)
+	// Each flow cookie is good for a single callback
+	http.SetCookie(w, &http.Cookie{HttpOnly: true, MaxAge: -1, Path: "/oidc", Name: oidcFlowCookie})
+	if err != nil || query.Get("state") != state {
+		http.Error(w, errOIDCFlow.Error(), http.StatusBadRequest)
+		return
+	}
+
+	claims, err := handler.OIDC.exchange(ctx, query.Get("code"), verifier, nonce)
+	if err != nil {
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+
+	u, err := handler.oidcUser(ctx, claims)
+	if errors.Is(err, errOIDCEmailUnverified) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	handler.completeLogin(w, r, u)
+}
+
+// oidcFlow reads the login in progress back from its cookie.
+func (handler *Handler) oidcFlow(r *http.Request) (state, nonce, verifier string, err error) {
+	cookie, err := r.Cookie(oidcFlowCookie)
+	if err != nil {
+		return "", "", "", err
+	}
+	token, err := handler.Keys.Verify(cookie.Value, oidcFlowTokenType)
+	if err != nil {
+		return "", "", "", err
+	}
+	claims := token.PrivateClaims()
+	state, _ = claims["oidcState"].(string)
+	nonce, _ = claims["oidcNonce"].(string)
+	verifier, _ = claims["oidcVerifier"].(string)
+	if state == "" || nonce == "" || verifier == "" {
+		return "", "", "", errOIDCFlow
+	}
+	return state, nonce, verifier, nil
+}
+
+var errOIDCEmailUnverified = errors.New("the provider has not verified this email address")
+
+// oidcUser returns the user linked to the external identity. An identity seen for
+// the first time is linked to the user with the same email, or to a new user, but
+// only if the provider has verified the address.
+func (handler *Handler) oidcUser(ctx context.Context, claims *oidcClaims) (*ent.User, error) {
+	var u *ent.User
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		id, err := tx.Identity.Query().
+			Where(identity.Issuer(handler.OIDC.issuer), identity.Subject(claims.Subject)).
+			WithUser().
+			Only(ctx)
+		if err == nil {
+			u = id.Edges.User
+			return id.Update().SetEmail(claims.Email).SetLastLoginAt(time.Now()).Exec(ctx)
+		}
+		if !ent.IsNotFound(err) {
+			return err
+		}
+
+		if !claims.EmailVerified || !validEmail(claims.Email) {
+			return errOIDCEmailUnverified
+		}
+		u, err = tx.User.Query().Where(user.Email(claims.Email)).Only(ctx)
+		switch {
+		case ent.IsNotFound(err):
+			name := claims.Name
+			if name == "" {
+				name, _, _ = strings.Cut(claims.Email, "@")
+			}
+			// No password until the user sets one through a password reset
+			u, err = tx.User.Create().
+				SetEmail(claims.Email).
+				SetName(name).
+				SetPassword("").
+				SetEmailVerified(true).
+				Save(ctx)
+			if err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case !u.EmailVerified:
+			// Whoever registered the unverified address can't be shown to own it, so
+			// their password, second factor, sessions and access tokens stop working
+			// once the real owner links it
+			u, err = u.Update().
+				SetEmailVerified(true).
+				SetPassword("").
+				SetTotpEnabled(false).
+				ClearTotpSecret().
+				ClearTotpLastStep().
+				Save(ctx)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.RecoveryCode.Delete().Where(recoverycode.HasUserWith(user.ID(u.ID))).Exec(ctx); err != nil {
+				return err
+			}
+			if err := signOutEverywhere(ctx, tx.Client(), u.ID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Identity.Create().
+			SetIssuer(handler.OIDC.issuer).
+			SetSubject(claims.Subject).
+			SetEmail(claims.Email).
+			SetUser(u).
+			Exec(ctx)
+	})
+	return u, err
+}
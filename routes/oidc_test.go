@@ -0,0 +1,188 @@
+package routes
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+	"todo/viewer"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"golang.org/x/oauth2"
+)
+
+// mockOIDCProvider is a minimal OpenID Connect provider issuing a single
+// authorization code.
+type mockOIDCProvider struct {
+	*httptest.Server
+	t         *testing.T
+	key       jwk.Key
+	clientID  string
+	code      string
+	challenge string
+	nonce     string
+}
+
+func newMockOIDCProvider(t *testing.T, clientID string) *mockOIDCProvider {
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwk.FromRaw(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key.Set(jwk.KeyIDKey, "test")
+	key.Set(jwk.AlgorithmKey, jwa.RS256)
+
+	p := &mockOIDCProvider{t: t, key: key, clientID: clientID, code: "test-code"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                p.URL,
+			"authorization_endpoint":                p.URL + "/authorize",
+			"token_endpoint":                        p.URL + "/token",
+			"jwks_uri":                              p.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		public, _ := p.key.PublicKey()
+		set := jwk.NewSet()
+		set.AddKey(public)
+		json.NewEncoder(w).Encode(set)
+	})
+	mux.HandleFunc("/token", p.token)
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+// authorize records what the client sent to the authorization endpoint.
+func (p *mockOIDCProvider) authorize(authURL string) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if got := u.Query().Get("code_challenge_method"); got != "S256" {
+		p.t.Fatalf("code_challenge_method = %q, want S256", got)
+	}
+	p.challenge = u.Query().Get("code_challenge")
+	p.nonce = u.Query().Get("nonce")
+}
+
+func (p *mockOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if r.PostForm.Get("code") != p.code || base64.RawURLEncoding.EncodeToString(sum[:]) != p.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	token, _ := jwt.NewBuilder().
+		Issuer(p.URL).
+		Subject("user-1").
+		Audience([]string{p.clientID}).
+		IssuedAt(time.Now()).
+		Expiration(time.Now().Add(time.Minute)).
+		Claim("nonce", p.nonce).
+		Claim("email", "ann@example.com").
+		Claim("email_verified", true).
+		Build()
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256, p.key))
+	if err != nil {
+		p.t.Fatal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     string(signed),
+	})
+}
+
+// TestOIDCExchange tests discovery, PKCE and ID token verification against a mock provider
+func TestOIDCExchange(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockOIDCProvider(t, "todo-client")
+	o, err := NewOIDC(ctx, provider.URL, "todo-client", "secret", "http://localhost:8080/oidc/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := oauth2.GenerateVerifier()
+	provider.authorize(o.authCodeURL("state", "nonce-1", verifier))
+
+	tests := []struct {
+		name     string
+		code     string
+		verifier string
+		nonce    string
+		wantErr  bool
+	}{
+		{"valid", provider.code, verifier, "nonce-1", false},
+		{"wrong code", "other", verifier, "nonce-1", true},
+		{"wrong verifier", provider.code, oauth2.GenerateVerifier(), "nonce-1", true},
+		{"wrong nonce", provider.code, verifier, "nonce-2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := o.exchange(ctx, tt.code, tt.verifier, tt.nonce)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("exchange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (claims.Subject != "user-1" || claims.Email != "ann@example.com" || !claims.EmailVerified) {
+				t.Errorf("exchange() claims = %+v", claims)
+			}
+		})
+	}
+}
+
+// TestOIDCTakeoverOfUnverifiedAccount tests that linking an address someone else
+// registered without verifying it leaves them no way back into the account
+func TestOIDCTakeoverOfUnverifiedAccount(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	handler.OIDC = &OIDC{issuer: "https://idp.example.com"}
+	client := handler.Client
+
+	squatter := client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").
+		SetTotpSecret("secret").SetTotpEnabled(true).SaveX(ctx)
+	s := client.Session.Create().SetUser(squatter).SaveX(ctx)
+	pat := client.AccessToken.Create().SetName("ci").SetTokenHash("pat").SetScopes([]string{"todos:read"}).
+		SetExpiresAt(time.Now().Add(time.Hour)).SetUser(squatter).SaveX(ctx)
+	client.RecoveryCode.Create().SetCodeHash("recovery").SetUser(squatter).SaveX(ctx)
+
+	u, err := handler.oidcUser(ctx, &oidcClaims{Subject: "user-1", Email: "ann@example.com", EmailVerified: true})
+	if err != nil {
+		t.Fatalf("oidcUser error: %v", err)
+	}
+	if u.ID != squatter.ID {
+		t.Fatalf("linked user %d, want %d", u.ID, squatter.ID)
+	}
+
+	u = client.User.GetX(ctx, u.ID)
+	if u.Password != "" || u.TotpEnabled || u.TotpSecret != nil || !u.EmailVerified {
+		t.Errorf("user = %+v, want no password or second factor and a verified address", u)
+	}
+	if client.Session.GetX(ctx, s.ID).RevokedAt == nil {
+		t.Error("session not revoked")
+	}
+	if client.AccessToken.GetX(ctx, pat.ID).RevokedAt == nil {
+		t.Error("access token not revoked")
+	}
+	if n := client.RecoveryCode.Query().CountX(ctx); n != 0 {
+		t.Errorf("%d recovery codes left, want 0", n)
+	}
+}
@@ -0,0 +1,194 @@
+package routes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"todo/ent"
+	"todo/ent/predicate"
+	"todo/ent/todo"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// todoSortFields maps the values accepted by the "sort" query parameter to Todo columns.
+var todoSortFields = map[string]string{
+	"position":   todo.FieldPosition,
+	"id":         todo.FieldID,
+	"title":      todo.FieldTitle,
+	"created_at": todo.FieldCreatedAt,
+	"due_at":     todo.FieldDueAt,
+}
+
+// todoPage describes one page of a GET /todos listing.
+type todoPage struct {
+	Field  string
+	Desc   bool
+	Limit  int
+	Cursor *todoCursor
+}
+
+// todoCursor marks the last Todo of a page. It is handed to clients as an opaque
+// base64 string and holds the sort column value plus the ID as a tie-breaker.
+type todoCursor struct {
+	Value *string `json:"v,omitempty"`
+	ID    int     `json:"id"`
+}
+
+// parseTodoPage reads the limit, cursor, sort and order query parameters.
+func parseTodoPage(query url.Values) (*todoPage, error) {
+	// Todos are listed in the user's manual order unless another sort is requested
+	page := &todoPage{Field: todo.FieldPosition, Limit: defaultPageLimit}
+
+	if sort := query.Get("sort"); sort != "" {
+		field, ok := todoSortFields[sort]
+		if !ok {
+			return nil, fmt.Errorf("invalid sort %q", sort)
+		}
+		page.Field = field
+	}
+
+	switch order := query.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		page.Desc = true
+	default:
+		return nil, fmt.Errorf("invalid order %q", order)
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		page.Limit = n
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		page.Cursor = &todoCursor{}
+		if err := json.Unmarshal(raw, page.Cursor); err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+	}
+
+	return page, nil
+}
+
+// apply orders the query and restricts it to the rows after the cursor. One extra
+// row is requested so the caller can tell whether another page exists.
+func (page *todoPage) apply(query *ent.TodoQuery) (*ent.TodoQuery, error) {
+	opts := []sql.OrderTermOption{sql.OrderAsc()}
+	if page.Desc {
+		opts = []sql.OrderTermOption{sql.OrderDesc()}
+	}
+	if page.Field == todo.FieldDueAt {
+		// Todos without a due date always come last
+		opts = append(opts, sql.OrderNullsLast())
+	}
+	query = query.Order(sql.OrderByField(page.Field, opts...).ToFunc())
+	if page.Field != todo.FieldID {
+		query = query.Order(sql.OrderByField(todo.FieldID, opts[0]).ToFunc())
+	}
+
+	if page.Cursor != nil {
+		after, err := page.afterCursor()
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(after)
+	}
+
+	return query.Limit(page.Limit + 1), nil
+}
+
+// afterCursor builds the keyset predicate selecting the rows that sort after the cursor.
+func (page *todoPage) afterCursor() (predicate.Todo, error) {
+	var value any
+	if page.Cursor.Value != nil {
+		switch page.Field {
+		case todo.FieldPosition:
+			n, err := strconv.ParseInt(*page.Cursor.Value, 10, 64)
+			if err != nil {
+				return nil, errors.New("invalid cursor")
+			}
+			value = n
+		case todo.FieldTitle:
+			value = *page.Cursor.Value
+		case todo.FieldCreatedAt, todo.FieldDueAt:
+			t, err := time.Parse(time.RFC3339Nano, *page.Cursor.Value)
+			if err != nil {
+				return nil, errors.New("invalid cursor")
+			}
+			value = t
+		}
+	}
+
+	return func(s *sql.Selector) {
+		after := sql.GT
+		if page.Desc {
+			after = sql.LT
+		}
+		col, idCol := s.C(page.Field), s.C(todo.FieldID)
+
+		switch {
+		case page.Field == todo.FieldID:
+			s.Where(after(idCol, page.Cursor.ID))
+		case value == nil:
+			// The cursor is already in the trailing block of todos without a due date
+			s.Where(sql.And(sql.IsNull(col), after(idCol, page.Cursor.ID)))
+		default:
+			preds := []*sql.Predicate{
+				after(col, value),
+				sql.And(sql.EQ(col, value), after(idCol, page.Cursor.ID)),
+			}
+			if page.Field == todo.FieldDueAt {
+				preds = append(preds, sql.IsNull(col))
+			}
+			s.Where(sql.Or(preds...))
+		}
+	}, nil
+}
+
+// nextCursor returns the encoded cursor pointing after the given Todo.
+func (page *todoPage) nextCursor(last *ent.Todo) string {
+	cursor := todoCursor{ID: last.ID}
+	switch page.Field {
+	case todo.FieldPosition:
+		v := strconv.FormatInt(last.Position, 10)
+		cursor.Value = &v
+	case todo.FieldTitle:
+		cursor.Value = &last.Title
+	case todo.FieldCreatedAt:
+		v := last.CreatedAt.Format(time.RFC3339Nano)
+		cursor.Value = &v
+	case todo.FieldDueAt:
+		if last.DueAt != nil {
+			v := last.DueAt.Format(time.RFC3339Nano)
+			cursor.Value = &v
+		}
+	}
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// setNextLink adds a Link header pointing at the next page of the current request.
+func setNextLink(w http.ResponseWriter, r *http.Request, cursor string) {
+	query := r.URL.Query()
+	query.Set("cursor", cursor)
+	next := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.String()))
+}
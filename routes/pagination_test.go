@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"net/url"
+	"testing"
+	"time"
+	"todo/ent"
+	"todo/ent/todo"
+)
+
+// TestParseTodoPage tests parsing of the pagination query parameters
+func TestParseTodoPage(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{"defaults", "", false},
+		{"sort and order", "sort=due_at&order=desc", false},
+		{"limit", "limit=20", false},
+		{"unknown sort", "sort=password", true},
+		{"unknown order", "order=sideways", true},
+		{"limit too large", "limit=1000", true},
+		{"limit not a number", "limit=ten", true},
+		{"garbage cursor", "cursor=!!!", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			if _, err := parseTodoPage(query); (err != nil) != tt.wantErr {
+				t.Errorf("parseTodoPage(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestTodoCursorRoundTrip tests that an issued cursor is accepted by the next request
+func TestTodoCursorRoundTrip(t *testing.T) {
+	due := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	last := &ent.Todo{ID: 42, Title: "Buy milk", CreatedAt: due, DueAt: &due}
+
+	for sort := range todoSortFields {
+		t.Run(sort, func(t *testing.T) {
+			page, err := parseTodoPage(url.Values{"sort": {sort}})
+			if err != nil {
+				t.Fatalf("parseTodoPage error: %v", err)
+			}
+			next, err := parseTodoPage(url.Values{"sort": {sort}, "cursor": {page.nextCursor(last)}})
+			if err != nil {
+				t.Fatalf("parseTodoPage with cursor error: %v", err)
+			}
+			if next.Cursor.ID != last.ID {
+				t.Errorf("cursor ID = %d, want %d", next.Cursor.ID, last.ID)
+			}
+			if _, err := next.afterCursor(); err != nil {
+				t.Errorf("afterCursor error: %v", err)
+			}
+			if page.Field != todo.FieldID && next.Cursor.Value == nil {
+				t.Errorf("cursor value missing for sort %q", sort)
+			}
+		})
+	}
+}
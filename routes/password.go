@@ -0,0 +1,176 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/passwordresettoken"
+	"todo/ent/user"
+	"todo/mailer"
+)
+
+const passwordResetTTL = time.Hour
+
+func (handler *Handler) passwords() *auth.PasswordHasher {
+	if handler.Passwords == nil {
+		return auth.DefaultPasswordHasher
+	}
+	return handler.Passwords
+}
+
+func (handler *Handler) passwordPolicy() *auth.PasswordPolicy {
+	if handler.PasswordPolicy == nil {
+		return &auth.DefaultPasswordPolicy
+	}
+	return handler.PasswordPolicy
+}
+
+// hashNewPassword checks a password the user is choosing against the policy and
+// hashes it. It writes the error response itself and reports whether it succeeded.
+func (handler *Handler) hashNewPassword(w http.ResponseWriter, password string) (string, bool) {
+	err := handler.passwordPolicy().Check(password)
+	var policyErr *auth.PolicyError
+	if errors.As(err, &policyErr) {
+		http.Error(w, policyErr.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	if err != nil {
+		http.Error(w, "Failed to check password", http.StatusInternalServerError)
+		return "", false
+	}
+
+	hash, err := handler.passwords().Hash(password)
+	if err != nil {
+		http.Error(w, "Failed to hash password", http.StatusInternalServerError)
+		return "", false
+	}
+	return hash, true
+}
+
+// ForgotPassword emails a single-use password reset link to the given address. It
+// responds the same way, and as quickly, whether or not the address is registered.
+func (handler *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var forgotDetails struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&forgotDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	email := forgotDetails.Email
+	sendInBackground("password reset", func(ctx context.Context) error {
+		return handler.sendPasswordReset(ctx, email)
+	})
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "If the address is registered, a password reset link has been sent",
+	})
+}
+
+func (handler *Handler) sendPasswordReset(ctx context.Context, email string) error {
+	u, err := handler.Client.User.Query().Where(user.Email(email)).Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return err
+	}
+	err = withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		// Only the most recently requested link stays valid
+		err := tx.PasswordResetToken.Update().
+			Where(passwordresettoken.HasUserWith(user.ID(u.ID)), passwordresettoken.UsedAtIsNil()).
+			SetUsedAt(time.Now()).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return tx.PasswordResetToken.Create().
+			SetTokenHash(auth.HashToken(token)).
+			SetExpiresAt(time.Now().Add(passwordResetTTL)).
+			SetUser(u).
+			Exec(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", handler.AppURL, url.QueryEscape(token))
+	return handler.Mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password. It expires in %v.\n\n%s\n\n"+
+			"If you didn't ask to reset your password, you can ignore this email.\n", u.Name, passwordResetTTL, link),
+	})
+}
+
+// ResetPassword sets a new password using a token from a password reset email and
+// logs the user out of all existing sessions.
+func (handler *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var resetDetails struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&resetDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hashedPassword, ok := handler.hashNewPassword(w, resetDetails.Password)
+	if !ok {
+		return
+	}
+
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		resetToken, err := tx.PasswordResetToken.Query().
+			Where(
+				passwordresettoken.TokenHash(auth.HashToken(resetDetails.Token)),
+				passwordresettoken.UsedAtIsNil(),
+				passwordresettoken.ExpiresAtGT(time.Now()),
+			).
+			WithUser().
+			Only(ctx)
+		if err != nil {
+			return err
+		}
+		if err := resetToken.Update().SetUsedAt(time.Now()).Exec(ctx); err != nil {
+			return err
+		}
+		// Proving access to the mailbox also lifts a lockout
+		err = resetToken.Edges.User.Update().
+			SetPassword(hashedPassword).
+			SetFailedLoginCount(0).
+			ClearLockedUntil().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		// Personal access tokens go too, in case whoever had the password made some
+		return signOutEverywhere(ctx, tx.Client(), resetToken.Edges.User.ID)
+	})
+	if ent.IsNotFound(err) {
+		http.Error(w, "invalid or expired reset token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Password reset successfully",
+	})
+}
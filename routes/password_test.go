@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	auth "todo/auth"
+	"todo/viewer"
+)
+
+// TestResetPasswordSignsOutEverywhere tests that a password reset ends the user's
+// sessions and revokes their personal access tokens
+func TestResetPasswordSignsOutEverywhere(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	s := handler.Client.Session.Create().SetUser(u).SaveX(ctx)
+	pat := handler.Client.AccessToken.Create().SetName("ci").SetTokenHash("pat").SetScopes([]string{"todos:read"}).
+		SetExpiresAt(time.Now().Add(time.Hour)).SetUser(u).SaveX(ctx)
+	token, err := auth.GenerateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.Client.PasswordResetToken.Create().SetTokenHash(auth.HashToken(token)).
+		SetExpiresAt(time.Now().Add(time.Hour)).SetUser(u).SaveX(ctx)
+
+	body := `{"token":"` + token + `","password":"a new correct horse"}`
+	req := httptest.NewRequest(http.MethodPost, "/password/reset", strings.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	handler.ResetPassword(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	if handler.Client.Session.GetX(ctx, s.ID).RevokedAt == nil {
+		t.Error("session not revoked")
+	}
+	if handler.Client.AccessToken.GetX(ctx, pat.ID).RevokedAt == nil {
+		t.Error("access token not revoked")
+	}
+}
@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"context"
+	"todo/ent"
+	"todo/ent/todo"
+	"todo/ent/user"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// positionGap is the distance left between neighbouring todos when positions are
+// assigned, so that a todo can be moved between two others by rewriting only its own row.
+const positionGap int64 = 1 << 16
+
+// positionBetween returns a position strictly between lo and hi, where a nil bound
+// means there is no todo on that side. It reports false when the gap is used up.
+func positionBetween(lo, hi *int64) (int64, bool) {
+	switch {
+	case lo == nil && hi == nil:
+		return positionGap, true
+	case lo == nil:
+		return *hi - positionGap, true
+	case hi == nil:
+		return *lo + positionGap, true
+	case *hi-*lo >= 2:
+		return *lo + (*hi-*lo)/2, true
+	default:
+		return 0, false
+	}
+}
+
+// nextPosition returns the position that appends a new todo to the end of the user's todos.
+func nextPosition(ctx context.Context, client *ent.Client, userID int) (int64, error) {
+	last, err := client.Todo.Query().
+		Where(todo.HasUserWith(user.ID(userID))).
+		Order(todo.ByPosition(sql.OrderDesc()), todo.ByID(sql.OrderDesc())).
+		First(ctx)
+	if ent.IsNotFound(err) {
+		return positionGap, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return last.Position + positionGap, nil
+}
+
+// movePosition computes the new position of moved so that it sorts directly after
+// (or before) anchor. If there is no room left between anchor and its neighbour
+// the user's todos are renumbered first.
+func movePosition(ctx context.Context, tx *ent.Tx, userID int, moved, anchor *ent.Todo, after bool) (int64, error) {
+	lo, hi, err := neighbourPositions(ctx, tx, userID, moved, anchor, after)
+	if err != nil {
+		return 0, err
+	}
+	if position, ok := positionBetween(lo, hi); ok {
+		return position, nil
+	}
+
+	if err := renumberPositions(ctx, tx, userID); err != nil {
+		return 0, err
+	}
+	if anchor, err = tx.Todo.Get(ctx, anchor.ID); err != nil {
+		return 0, err
+	}
+	if lo, hi, err = neighbourPositions(ctx, tx, userID, moved, anchor, after); err != nil {
+		return 0, err
+	}
+	position, _ := positionBetween(lo, hi)
+	return position, nil
+}
+
+// neighbourPositions returns the positions on either side of the slot directly after
+// (or before) anchor, ignoring the todo being moved. A nil bound means there is no todo on that side.
+func neighbourPositions(ctx context.Context, tx *ent.Tx, userID int, moved, anchor *ent.Todo, after bool) (lo, hi *int64, err error) {
+	query := tx.Todo.Query().Where(
+		todo.HasUserWith(user.ID(userID)),
+		todo.IDNEQ(moved.ID),
+	)
+	if after {
+		query = query.
+			Where(todo.Or(
+				todo.PositionGT(anchor.Position),
+				todo.And(todo.Position(anchor.Position), todo.IDGT(anchor.ID)),
+			)).
+			Order(todo.ByPosition(), todo.ByID())
+	} else {
+		query = query.
+			Where(todo.Or(
+				todo.PositionLT(anchor.Position),
+				todo.And(todo.Position(anchor.Position), todo.IDLT(anchor.ID)),
+			)).
+			Order(todo.ByPosition(sql.OrderDesc()), todo.ByID(sql.OrderDesc()))
+	}
+	neighbour, err := query.First(ctx)
+	if err != nil && !ent.IsNotFound(err) {
+		return nil, nil, err
+	}
+
+	var other *int64
+	if neighbour != nil {
+		other = &neighbour.Position
+	}
+	if after {
+		return &anchor.Position, other, nil
+	}
+	return other, &anchor.Position, nil
+}
+
+// renumberPositions spreads the user's todos out again, keeping their current order.
+func renumberPositions(ctx context.Context, tx *ent.Tx, userID int) error {
+	todos, err := tx.Todo.Query().
+		Where(todo.HasUserWith(user.ID(userID))).
+		Order(todo.ByPosition(), todo.ByID()).
+		All(ctx)
+	if err != nil {
+		return err
+	}
+	for i, t := range todos {
+		if err := tx.Todo.UpdateOneID(t.ID).SetPosition(int64(i+1) * positionGap).Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package routes
+
+import "testing"
+
+// TestPositionBetween tests picking a position between two neighbours
+func TestPositionBetween(t *testing.T) {
+	p := func(n int64) *int64 { return &n }
+
+	tests := []struct {
+		name   string
+		lo, hi *int64
+		want   int64
+		wantOK bool
+	}{
+		{"empty list", nil, nil, positionGap, true},
+		{"move to front", nil, p(positionGap), 0, true},
+		{"move to back", p(positionGap), nil, 2 * positionGap, true},
+		{"between neighbours", p(100), p(200), 150, true},
+		{"gap of two", p(100), p(102), 101, true},
+		{"adjacent", p(100), p(101), 0, false},
+		{"equal", p(0), p(0), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := positionBetween(tt.lo, tt.hi)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("positionBetween() = (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"todo/ent"
+	"todo/ent/todo"
+
+	"github.com/teambition/rrule-go"
+)
+
+// parseRecurrence validates an RFC 5545 RRULE such as "FREQ=WEEKLY;BYDAY=MO,WE" and
+// returns it anchored at dtstart, in the form stored in the Todo recurrence field.
+func parseRecurrence(rule string, dtstart time.Time) (string, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if strings.Contains(rule, "\n") {
+		return "", fmt.Errorf("invalid recurrence: expected a single RRULE, the series starts at due_at")
+	}
+	option, err := rrule.StrToROption(rule)
+	if err != nil {
+		return "", fmt.Errorf("invalid recurrence: %w", err)
+	}
+	option.Dtstart = dtstart.UTC().Truncate(time.Second)
+	if _, err := rrule.NewRRule(*option); err != nil {
+		return "", fmt.Errorf("invalid recurrence: %w", err)
+	}
+	return option.String(), nil
+}
+
+// nextOccurrence returns the first date scheduled by a stored recurrence after the
+// given one. It reports false when the series has ended.
+func nextOccurrence(recurrence string, after time.Time) (time.Time, bool, error) {
+	rule, err := rrule.StrToRRule(recurrence)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	next := rule.After(after, false)
+	return next, !next.IsZero(), nil
+}
+
+// scheduleNextOccurrence creates the next occurrence of a recurring Todo that was just
+// completed, copying its details and shifting its dates to the next scheduled date.
+func (handler *Handler) scheduleNextOccurrence(ctx context.Context, tx *ent.Tx, t *ent.Todo) error {
+	current := t.OccurrenceAt
+	if current == nil {
+		current = t.DueAt
+	}
+	if t.Recurrence == nil || current == nil {
+		return nil
+	}
+	next, ok, err := nextOccurrence(*t.Recurrence, *current)
+	if err != nil || !ok {
+		return err
+	}
+
+	seriesID := t.ID
+	if t.SeriesID != nil {
+		seriesID = *t.SeriesID
+	}
+
+	// Completing, reopening and completing again must not create the occurrence twice
+	exists, err := tx.Todo.Query().
+		Where(todo.SeriesID(seriesID), todo.OccurrenceAt(next)).
+		Exist(ctx)
+	if err != nil || exists {
+		return err
+	}
+
+	tagIDs, err := tx.Todo.QueryTags(t).IDs(ctx)
+	if err != nil {
+		return err
+	}
+	userID, err := tx.Todo.QueryUser(t).OnlyID(ctx)
+	if err != nil {
+		return err
+	}
+
+	create := tx.Todo.Create().
+		SetTitle(t.Title).
+		SetStatus(handler.workflow().Initial).
+		SetPriority(t.Priority).
+		SetPosition(t.Position).
+		SetAutoComplete(t.AutoComplete).
+		SetNillableListID(t.ListID).
+		SetNillableParentID(t.ParentID).
+		SetRecurrence(*t.Recurrence).
+		SetSeriesID(seriesID).
+		SetOccurrenceAt(next).
+		SetDueAt(next).
+		AddTagIDs(tagIDs...).
+		SetUserID(userID)
+	if t.StartAt != nil && t.DueAt != nil {
+		// Keep the same lead time between start and due date
+		create.SetStartAt(next.Add(t.StartAt.Sub(*t.DueAt)))
+	}
+	if err := create.Exec(ctx); err != nil {
+		return err
+	}
+
+	// The completed occurrence joins the series if it started it
+	if t.SeriesID == nil {
+		return tx.Todo.UpdateOneID(t.ID).SetSeriesID(seriesID).Exec(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNextOccurrence tests scheduling the next occurrence of common recurrence rules
+func TestNextOccurrence(t *testing.T) {
+	// Friday 1 March 2024, 09:00 UTC
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		rule   string
+		after  time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{"daily", "FREQ=DAILY", start, start.AddDate(0, 0, 1), true},
+		{"weekly on monday and wednesday", "RRULE:FREQ=WEEKLY;BYDAY=MO,WE", start, time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC), true},
+		{"monthly on the last friday", "FREQ=MONTHLY;BYDAY=-1FR", start, time.Date(2024, 3, 29, 9, 0, 0, 0, time.UTC), true},
+		{"series ended by count", "FREQ=DAILY;COUNT=2", start.AddDate(0, 0, 1), time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurrence, err := parseRecurrence(tt.rule, start)
+			if err != nil {
+				t.Fatalf("parseRecurrence(%q) error: %v", tt.rule, err)
+			}
+			got, ok, err := nextOccurrence(recurrence, tt.after)
+			if err != nil {
+				t.Fatalf("nextOccurrence(%q) error: %v", recurrence, err)
+			}
+			if !got.Equal(tt.want) || ok != tt.wantOK {
+				t.Errorf("nextOccurrence(%q) = (%v, %v), want (%v, %v)", recurrence, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestParseRecurrenceInvalid tests that malformed rules are rejected
+func TestParseRecurrenceInvalid(t *testing.T) {
+	for _, rule := range []string{"", "BYDAY=MO", "FREQ=SOMETIMES", "FREQ=DAILY;BYDAY", "DTSTART:20240301T090000Z\nRRULE:FREQ=DAILY"} {
+		if _, err := parseRecurrence(rule, time.Now()); err == nil {
+			t.Errorf("parseRecurrence(%q) succeeded, want error", rule)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package routes
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	auth "todo/auth"
+	"todo/ent/enttest"
+	"todo/mailer"
+	"todo/viewer"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newTestHandler returns a Handler backed by a fresh in-memory database, shared by
+// all of its connections so emails sent in the background see the same data.
+func newTestHandler(t *testing.T) *Handler {
+	client := enttest.Open(t, "sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared&_fk=1", t.Name()))
+	t.Cleanup(func() { client.Close() })
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwk.FromRaw(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := auth.NewKeySet(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Handler{
+		Client:    client,
+		Keys:      keys,
+		Passwords: &auth.PasswordHasher{Algorithm: auth.Bcrypt, BcryptCost: 4},
+		Mailer:    mailer.NewLogMailer(io.Discard),
+	}
+}
+
+// newUserRouter returns a router authenticating every request as the user, in place
+// of the token middleware, with the user as the viewer.
+func newUserRouter(handler *Handler, userID int) chi.Router {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey, userID)))
+		})
+	})
+	r.Use(handler.ViewerMiddleware)
+	return r
+}
+
+// serve sends a request with an optional JSON body to h and returns the response.
+func serve(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+// decode reads the JSON body of a response, failing the test if it isn't a T.
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body, err)
+	}
+	return v
+}
+
+// TestNoPasswordInResponses calls the handlers that return users, todos, lists and
+// tags against an in-memory database and checks no response contains a password hash
+func TestNoPasswordInResponses(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	client := handler.Client
+	passwords := handler.Passwords
+
+	hash, err := passwords.Hash("correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ann := client.User.Create().SetName("ann").SetEmail("ann@example.com").SetAge(30).SetPassword(hash).SaveX(ctx)
+	client.User.Create().SetName("bob").SetEmail("bob@example.com").SetAge(31).SetPassword(hash).SaveX(ctx)
+	work := client.List.Create().SetName("Work").SetOwner(ann).SaveX(ctx)
+	urgent := client.Tag.Create().SetName("urgent").SetOwner(ann).SaveX(ctx)
+	parent := client.Todo.Create().SetTitle("Ship it").SetUser(ann).SetList(work).AddTags(urgent).SaveX(ctx)
+	client.Todo.Create().SetTitle("Write tests").SetUser(ann).SetParent(parent).SaveX(ctx)
+	current := client.Session.Create().SetUser(ann).SaveX(ctx)
+
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(SystemViewer)
+		r.Post("/register", handler.Register)
+		r.Post("/login", handler.Login)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), userIDKey, ann.ID)
+				ctx = context.WithValue(ctx, sessionIDKey, current.ID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Use(handler.ViewerMiddleware)
+		r.Get("/users", handler.GetAllUsers)
+		r.Get("/users/{name}", handler.QueryUser)
+		r.Get("/sessions", handler.GetSessions)
+		r.Post("/todos", handler.CreateTodo)
+		r.Get("/todos", handler.GetTodos)
+		r.Get("/todos/{id}", handler.GetTodo)
+		r.Patch("/todos/{id}", handler.UpdateTodo)
+		r.Get("/lists", handler.GetLists)
+		r.Get("/lists/{id}", handler.GetList)
+		r.Get("/lists/{id}/todos", handler.GetListTodos)
+		r.Get("/tags", handler.GetTags)
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{"POST", "/register", `{"email":"cat@example.com","password":"correct horse battery","name":"cat","age":32}`},
+		{"POST", "/login", `{"email":"ann@example.com","password":"correct horse battery"}`},
+		{"GET", "/users", ""},
+		{"GET", "/users/bob", ""},
+		{"GET", "/sessions", ""},
+		{"POST", "/todos", `{"title":"Review"}`},
+		{"GET", "/todos", ""},
+		{"GET", "/todos?tree=true", ""},
+		{"GET", fmt.Sprintf("/todos/%d", parent.ID), ""},
+		{"PATCH", fmt.Sprintf("/todos/%d", parent.ID), `{"title":"Ship it today"}`},
+		{"GET", "/lists", ""},
+		{"GET", fmt.Sprintf("/lists/%d", work.ID), ""},
+		{"GET", fmt.Sprintf("/lists/%d/todos", work.ID), ""},
+		{"GET", "/tags", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if w.Code >= 300 {
+				t.Fatalf("status %d: %s", w.Code, w.Body)
+			}
+			body := w.Body.String()
+			if strings.Contains(body, hash) || strings.Contains(strings.ToLower(body), `"password"`) {
+				t.Errorf("response contains a password: %s", body)
+			}
+		})
+	}
+}
+
+// todoListing is the body of the todo listing endpoints.
+type todoListing struct {
+	Todos      []TodoResponse `json:"todos"`
+	NextCursor string         `json:"next_cursor"`
+}
@@ -0,0 +1,119 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+	"todo/ent/session"
+	"todo/ent/user"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/go-chi/chi/v5"
+)
+
+// SessionResponse is the JSON representation of one of the user's logins.
+type SessionResponse struct {
+	ID         int       `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// Current marks the session the request was made from
+	Current bool `json:"current"`
+}
+
+// GetSessions lists the user's active sessions, most recently used first.
+func (handler *Handler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return
+	}
+	currentID, _ := ctx.Value(sessionIDKey).(int)
+
+	// Sessions idle for longer than a refresh token lives can't be resumed
+	sessions, err := handler.Client.Session.Query().
+		Where(
+			session.HasUserWith(user.ID(userID)),
+			session.RevokedAtIsNil(),
+			session.LastSeenAtGT(time.Now().Add(-refreshTokenTTL)),
+		).
+		Order(session.ByLastSeenAt(sql.OrderDesc())).
+		All(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]SessionResponse, len(sessions))
+	for i, s := range sessions {
+		responses[i] = SessionResponse{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			Current:    s.ID == currentID,
+		}
+	}
+	json.NewEncoder(w).Encode(responses)
+}
+
+// DeleteSession logs the user out of one of their sessions.
+func (handler *Handler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	exists, err := handler.Client.Session.Query().
+		Where(session.ID(sessionID), session.HasUserWith(user.ID(userID)), session.RevokedAtIsNil()).
+		Exist(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		http.Error(w, "Session not found or does not belong to user", http.StatusNotFound)
+		return
+	}
+
+	if err := revokeSessions(ctx, handler.Client, session.ID(sessionID)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if currentID, _ := ctx.Value(sessionIDKey).(int); currentID == sessionID {
+		clearAuthCookies(w)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Session revoked"})
+}
+
+// DeleteSessions logs the user out everywhere, including the current session.
+func (handler *Handler) DeleteSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	if err := revokeSessions(ctx, handler.Client, session.HasUserWith(user.ID(userID))); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	clearAuthCookies(w)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "All sessions revoked"})
+}
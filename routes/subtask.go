@@ -0,0 +1,100 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+	"todo/ent"
+	"todo/ent/todo"
+	"todo/ent/user"
+)
+
+var errParentCycle = errors.New("a todo cannot be nested under itself or one of its subtasks")
+
+// checkParent ensures that nesting the Todo with the given ID (0 for a new Todo)
+// under parentID does not create a cycle.
+func checkParent(ctx context.Context, client *ent.Client, todoID, parentID int) error {
+	for id := &parentID; id != nil; {
+		if *id == todoID {
+			return errParentCycle
+		}
+		ancestor, err := client.Todo.Get(ctx, *id)
+		if err != nil {
+			return err
+		}
+		id = ancestor.ParentID
+	}
+	return nil
+}
+
+// todoTree attaches every descendant of roots to its parent's response.
+func todoTree(ctx context.Context, client *ent.Client, roots []*ent.Todo, now time.Time) ([]TodoResponse, error) {
+	children := make(map[int][]*ent.Todo)
+	ids := make([]int, len(roots))
+	for i, root := range roots {
+		ids[i] = root.ID
+	}
+	for len(ids) > 0 {
+		level, err := client.Todo.Query().
+			Where(todo.ParentIDIn(ids...)).
+			Order(todo.ByID()).
+			WithTags().
+			All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ids = ids[:0]
+		for _, child := range level {
+			children[*child.ParentID] = append(children[*child.ParentID], child)
+			ids = append(ids, child.ID)
+		}
+	}
+
+	var build func(todos []*ent.Todo) []TodoResponse
+	build = func(todos []*ent.Todo) []TodoResponse {
+		responses := newTodoResponses(todos, now)
+		for i := range responses {
+			responses[i].Children = build(children[responses[i].ID])
+		}
+		return responses
+	}
+	return build(roots), nil
+}
+
+// checkParentTodo ensures the parent Todo belongs to the user making the request and
+// that nesting the Todo with the given ID (0 for a new Todo) under it does not create
+// a cycle. On failure it writes the error response and returns false.
+func (handler *Handler) checkParentTodo(w http.ResponseWriter, r *http.Request, todoID, parentID int) bool {
+	ctx := r.Context()
+
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return false
+	}
+
+	exists, err := handler.Client.Todo.
+		Query().
+		Where(todo.ID(parentID), todo.HasUserWith(user.ID(userID))).
+		Exist(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if !exists {
+		http.Error(w, "Parent todo not found or does not belong to user", http.StatusNotFound)
+		return false
+	}
+
+	if err := checkParent(ctx, handler.Client, todoID, parentID); err != nil {
+		if errors.Is(err, errParentCycle) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,208 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"todo/ent"
+	"todo/ent/tag"
+	"todo/ent/user"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (handler *Handler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	// Get the userID from the context
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var tagDetails struct {
+		Name  string  `json:"name"`
+		Color *string `json:"color"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&tagDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	newTag, err := handler.Client.Tag.Create().
+		SetName(tagDetails.Name).
+		SetNillableColor(tagDetails.Color).
+		SetOwnerID(userID).
+		Save(ctx)
+	if ent.IsValidationError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ent.IsConstraintError(err) {
+		http.Error(w, "A tag with this name already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newTagResponse(newTag))
+}
+
+func (handler *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	// Get the userID from the context
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	tags, err := handler.Client.Tag.Query().
+		Where(tag.HasOwnerWith(user.ID(userID))).
+		Order(tag.ByName()).
+		All(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]TagResponse, len(tags))
+	for i, t := range tags {
+		responses[i] = newTagResponse(t)
+	}
+	json.NewEncoder(w).Encode(responses)
+}
+
+// UpdateTag renames and/or recolors a tag. A null color removes it.
+func (handler *Handler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tagItem, ok := handler.userTag(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	var tagDetails struct {
+		Name  *string          `json:"name"`
+		Color nullable[string] `json:"color"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&tagDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	update := tagItem.Update().SetNillableName(tagDetails.Name)
+	if tagDetails.Color.Set {
+		if tagDetails.Color.Value == nil {
+			update.ClearColor()
+		} else {
+			update.SetColor(*tagDetails.Color.Value)
+		}
+	}
+
+	updatedTag, err := update.Save(ctx)
+	if ent.IsValidationError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ent.IsConstraintError(err) {
+		http.Error(w, "A tag with this name already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newTagResponse(updatedTag))
+}
+
+func (handler *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tagItem, ok := handler.userTag(w, r, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	// Deleting the tag also detaches it from all of its todos
+	if err := handler.Client.Tag.DeleteOne(tagItem).Exec(ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Tag deleted"})
+}
+
+func (handler *Handler) AttachTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	todoItem, ok := handler.userTodo(w, r)
+	if !ok {
+		return
+	}
+	tagItem, ok := handler.userTag(w, r, chi.URLParam(r, "tagID"))
+	if !ok {
+		return
+	}
+
+	// Attaching a tag twice violates the join table's primary key, which we treat as a no-op
+	err := todoItem.Update().AddTags(tagItem).Exec(ctx)
+	if err != nil && !ent.IsConstraintError(err) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Tag attached"})
+}
+
+func (handler *Handler) DetachTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	todoItem, ok := handler.userTodo(w, r)
+	if !ok {
+		return
+	}
+	tagItem, ok := handler.userTag(w, r, chi.URLParam(r, "tagID"))
+	if !ok {
+		return
+	}
+
+	if err := todoItem.Update().RemoveTags(tagItem).Exec(ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Tag detached"})
+}
+
+// userTag loads the Tag with the given ID and ensures it belongs to the user
+// making the request. On failure it writes the error response and returns false.
+func (handler *Handler) userTag(w http.ResponseWriter, r *http.Request, id string) (*ent.Tag, bool) {
+	ctx := r.Context()
+
+	tagID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	// Get the userID from the context to ensure the Tag belongs to the user making the request
+	userID, ok := ctx.Value(userIDKey).(int)
+	if !ok {
+		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	tagItem, err := handler.Client.Tag.
+		Query().
+		Where(tag.ID(tagID), tag.HasOwnerWith(user.ID(userID))).
+		Only(ctx)
+	if err != nil {
+		http.Error(w, "Tag not found or does not belong to user", http.StatusNotFound)
+		return nil, false
+	}
+
+	return tagItem, true
+}
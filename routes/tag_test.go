@@ -0,0 +1,149 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+	"todo/viewer"
+)
+
+// TestTagLifecycle tests creating, editing, attaching, detaching and deleting tags,
+// and that other users' tags can't be touched
+func TestTagLifecycle(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	ann := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	bob := handler.Client.User.Create().SetName("bob").SetEmail("bob@example.com").SetPassword("hash").SaveX(ctx)
+	bobTag := handler.Client.Tag.Create().SetName("work").SetOwner(bob).SaveX(ctx)
+	todoItem := handler.Client.Todo.Create().SetTitle("Ship it").SetUser(ann).SaveX(ctx)
+
+	r := newUserRouter(handler, ann.ID)
+	r.Post("/tags", handler.CreateTag)
+	r.Get("/tags", handler.GetTags)
+	r.Patch("/tags/{id}", handler.UpdateTag)
+	r.Delete("/tags/{id}", handler.DeleteTag)
+	r.Put("/todos/{id}/tags/{tagID}", handler.AttachTag)
+	r.Delete("/todos/{id}/tags/{tagID}", handler.DetachTag)
+
+	rec := serve(r, http.MethodPost, "/tags", `{"name":"work","color":"#ff0000"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create: status = %d: %s", rec.Code, rec.Body)
+	}
+	work := decode[TagResponse](t, rec)
+	if rec := serve(r, http.MethodPost, "/tags", `{"name":"work"}`); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate name: status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	home := decode[TagResponse](t, serve(r, http.MethodPost, "/tags", `{"name":"home"}`))
+	workPath, homePath := "/tags/"+strconv.Itoa(work.ID), "/tags/"+strconv.Itoa(home.ID)
+
+	if rec := serve(r, http.MethodPatch, homePath, `{"name":"work"}`); rec.Code != http.StatusConflict {
+		t.Errorf("renaming to a duplicate: status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if got := decode[TagResponse](t, serve(r, http.MethodPatch, workPath, `{"name":"job","color":null}`)); got.Name != "job" || got.Color != "" {
+		t.Errorf("updated = %+v, want job without a color", got)
+	}
+	if tags := decode[[]TagResponse](t, serve(r, http.MethodGet, "/tags", "")); len(tags) != 2 || tags[0].Name != "home" {
+		t.Errorf("tags = %+v, want home and job", tags)
+	}
+
+	attachPath := "/todos/" + strconv.Itoa(todoItem.ID) + "/tags/" + strconv.Itoa(work.ID)
+	for i := 0; i < 2; i++ {
+		// Attaching twice is a no-op
+		if rec := serve(r, http.MethodPut, attachPath, ""); rec.Code != http.StatusOK {
+			t.Fatalf("attach: status = %d: %s", rec.Code, rec.Body)
+		}
+	}
+	if n := todoItem.QueryTags().CountX(ctx); n != 1 {
+		t.Errorf("todo has %d tags after attaching, want 1", n)
+	}
+	if rec := serve(r, http.MethodDelete, attachPath, ""); rec.Code != http.StatusOK {
+		t.Fatalf("detach: status = %d: %s", rec.Code, rec.Body)
+	}
+	if n := todoItem.QueryTags().CountX(ctx); n != 0 {
+		t.Errorf("todo has %d tags after detaching, want 0", n)
+	}
+
+	// Deleting a tag detaches it from its todos
+	serve(r, http.MethodPut, attachPath, "")
+	if rec := serve(r, http.MethodDelete, workPath, ""); rec.Code != http.StatusOK {
+		t.Fatalf("delete: status = %d: %s", rec.Code, rec.Body)
+	}
+	if n := todoItem.QueryTags().CountX(ctx); n != 0 {
+		t.Errorf("todo has %d tags after deleting the tag, want 0", n)
+	}
+	if rec := serve(r, http.MethodPatch, workPath, `{"name":"gone"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("update deleted tag: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	other := "/tags/" + strconv.Itoa(bobTag.ID)
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodPatch, other, `{"name":"mine"}`},
+		{http.MethodDelete, other, ""},
+		{http.MethodPut, "/todos/" + strconv.Itoa(todoItem.ID) + "/tags/" + strconv.Itoa(bobTag.ID), ""},
+	}
+	for _, tt := range tests {
+		if rec := serve(r, tt.method, tt.path, tt.body); rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s with another user's tag: status = %d, want %d", tt.method, tt.path, rec.Code, http.StatusNotFound)
+		}
+	}
+	if got := handler.Client.Tag.GetX(ctx, bobTag.ID); got.Name != "work" {
+		t.Errorf("other user's tag changed: %+v", got)
+	}
+}
+
+// TestTagFilter tests filtering todos by tag, requiring all of the given tags by
+// default and any of them with tag_mode=any
+func TestTagFilter(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	ann := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	bob := handler.Client.User.Create().SetName("bob").SetEmail("bob@example.com").SetPassword("hash").SaveX(ctx)
+	work := handler.Client.Tag.Create().SetName("work").SetOwner(ann).SaveX(ctx)
+	urgent := handler.Client.Tag.Create().SetName("urgent").SetOwner(ann).SaveX(ctx)
+	bobWork := handler.Client.Tag.Create().SetName("work").SetOwner(bob).SaveX(ctx)
+	handler.Client.Todo.Create().SetTitle("Report").SetUser(ann).AddTags(work).SaveX(ctx)
+	handler.Client.Todo.Create().SetTitle("Outage").SetUser(ann).AddTags(work, urgent).SaveX(ctx)
+	handler.Client.Todo.Create().SetTitle("Dentist").SetUser(ann).AddTags(urgent).SaveX(ctx)
+	handler.Client.Todo.Create().SetTitle("Groceries").SetUser(ann).SaveX(ctx)
+	handler.Client.Todo.Create().SetTitle("Bob's report").SetUser(bob).AddTags(bobWork).SaveX(ctx)
+
+	r := newUserRouter(handler, ann.ID)
+	r.Get("/todos", handler.GetTodos)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"tag=work", []string{"Outage", "Report"}},
+		{"tag=work&tag=urgent", []string{"Outage"}},
+		{"tag=work&tag=urgent&tag_mode=all", []string{"Outage"}},
+		{"tag=work&tag=urgent&tag_mode=any", []string{"Dentist", "Outage", "Report"}},
+		{"tag=missing&tag_mode=any", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := serve(r, http.MethodGet, "/todos?"+tt.query, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			var got []string
+			for _, item := range decode[todoListing](t, rec).Todos {
+				got = append(got, item.Title)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("todos = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if rec := serve(r, http.MethodGet, "/todos?tag=work&tag_mode=some", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid tag_mode: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
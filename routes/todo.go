@@ -2,9 +2,13 @@ package routes
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
+	"todo/ent"
+	"todo/ent/tag"
 	"todo/ent/todo"
 	"todo/ent/user"
 
@@ -22,25 +26,85 @@ func (handler *Handler) CreateTodo(w http.ResponseWriter, r *http.Request) {
 
 	// Parse the request body to get the todo details
 	var todoDetails struct {
-		Title string `json:"title"`
+		Title        string         `json:"title"`
+		Priority     *todo.Priority `json:"priority"`
+		StartAt      *time.Time     `json:"start_at"`
+		DueAt        *time.Time     `json:"due_at"`
+		ListID       *int           `json:"list_id"`
+		ParentID     *int           `json:"parent_id"`
+		AutoComplete *bool          `json:"auto_complete"`
+		Recurrence   *string        `json:"recurrence"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&todoDetails); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if todoDetails.Priority != nil {
+		if err := todo.PriorityValidator(*todoDetails.Priority); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if todoDetails.ListID != nil && !handler.checkListOwner(w, r, *todoDetails.ListID) {
+		return
+	}
+	if todoDetails.ParentID != nil && !handler.checkParentTodo(w, r, 0, *todoDetails.ParentID) {
+		return
+	}
+
+	// A recurring Todo's series starts at its first due date
+	var recurrence *string
+	var occurrenceAt *time.Time
+	if todoDetails.Recurrence != nil {
+		if todoDetails.DueAt == nil {
+			http.Error(w, "A recurring todo needs a due_at", http.StatusBadRequest)
+			return
+		}
+		rule, err := parseRecurrence(*todoDetails.Recurrence, *todoDetails.DueAt)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		recurrence, occurrenceAt = &rule, todoDetails.DueAt
+	}
+	if todoDetails.StartAt != nil && todoDetails.DueAt != nil && todoDetails.DueAt.Before(*todoDetails.StartAt) {
+		http.Error(w, "due_at must not be before start_at", http.StatusBadRequest)
+		return
+	}
+
+	// New todos are added to the end of the user's manual ordering
+	position, err := nextPosition(ctx, handler.Client, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	// Create the new Todo and link it to the User using SetUserID
 	newTodo, err := handler.Client.Todo.Create().
 		SetTitle(todoDetails.Title).
+		SetStatus(handler.workflow().Initial).
+		SetNillablePriority(todoDetails.Priority).
+		SetPosition(position).
+		SetNillableStartAt(todoDetails.StartAt).
+		SetNillableDueAt(todoDetails.DueAt).
+		SetNillableListID(todoDetails.ListID).
+		SetNillableParentID(todoDetails.ParentID).
+		SetNillableAutoComplete(todoDetails.AutoComplete).
+		SetNillableRecurrence(recurrence).
+		SetNillableOccurrenceAt(occurrenceAt).
 		SetUserID(userID). // Correctly link the Todo to the User
 		Save(ctx)
+	if ent.IsConstraintError(err) {
+		http.Error(w, "A todo with this title already exists", http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Encode and send the newly created Todo as a response
-	json.NewEncoder(w).Encode(newTodo)
+	json.NewEncoder(w).Encode(newTodoResponse(newTodo, time.Now()))
 }
 
 func (handler *Handler) GetTodos(w http.ResponseWriter, r *http.Request) {
@@ -52,31 +116,467 @@ func (handler *Handler) GetTodos(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
 		return
 	}
-	todos, err := handler.Client.Todo.Query().Where(todo.HasUserWith(user.ID(userID))).All(ctx)
+	query := handler.Client.Todo.Query().Where(todo.HasUserWith(user.ID(userID)))
+
+	// Optional list filter, e.g. "/todos?list=3"
+	if listParam := r.URL.Query().Get("list"); listParam != "" {
+		listID, err := strconv.Atoi(listParam)
+		if err != nil {
+			http.Error(w, "Invalid list ID", http.StatusBadRequest)
+			return
+		}
+		query = query.Where(todo.ListID(listID))
+	}
+
+	handler.writeTodos(w, r, query)
+}
+
+// writeTodos applies the filter, sort and pagination query parameters shared by
+// every todo listing endpoint to query and writes the resulting page.
+func (handler *Handler) writeTodos(w http.ResponseWriter, r *http.Request, query *ent.TodoQuery) {
+	ctx := r.Context()
+
+	// Optional status filter, e.g. "/todos?status=todo&status=in_progress"
+	if statuses := r.URL.Query()["status"]; len(statuses) > 0 {
+		for _, status := range statuses {
+			if !handler.workflow().HasState(status) {
+				http.Error(w, fmt.Sprintf("Invalid status %q", status), http.StatusBadRequest)
+				return
+			}
+		}
+		query = query.Where(todo.StatusIn(statuses...))
+	}
+
+	// Optional priority filter, e.g. "/todos?priority=high&priority=urgent"
+	if priorities := r.URL.Query()["priority"]; len(priorities) > 0 {
+		values := make([]todo.Priority, len(priorities))
+		for i, priority := range priorities {
+			values[i] = todo.Priority(priority)
+			if err := todo.PriorityValidator(values[i]); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		query = query.Where(todo.PriorityIn(values...))
+	}
+
+	// Optional tag filter by name, e.g. "/todos?tag=work&tag=urgent". By default a Todo
+	// must carry every given tag; "tag_mode=any" matches todos with at least one of them.
+	if tags := r.URL.Query()["tag"]; len(tags) > 0 {
+		switch mode := r.URL.Query().Get("tag_mode"); mode {
+		case "", "all":
+			for _, name := range tags {
+				query = query.Where(todo.HasTagsWith(tag.Name(name)))
+			}
+		case "any":
+			query = query.Where(todo.HasTagsWith(tag.NameIn(tags...)))
+		default:
+			http.Error(w, "Invalid tag_mode, expected all or any", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Optional due date filters, e.g. "/todos?due_after=2024-03-01T00:00:00Z&due_before=2024-03-08T00:00:00Z"
+	if dueBefore := r.URL.Query().Get("due_before"); dueBefore != "" {
+		t, err := time.Parse(time.RFC3339, dueBefore)
+		if err != nil {
+			http.Error(w, "Invalid due_before, expected RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		query = query.Where(todo.DueAtLT(t))
+	}
+	if dueAfter := r.URL.Query().Get("due_after"); dueAfter != "" {
+		t, err := time.Parse(time.RFC3339, dueAfter)
+		if err != nil {
+			http.Error(w, "Invalid due_after, expected RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		query = query.Where(todo.DueAtGT(t))
+	}
+
+	// With "tree=true" only top-level todos are paged and their subtasks are nested under them
+	tree := r.URL.Query().Get("tree") == "true"
+	if tree {
+		query = query.Where(todo.ParentIDIsNil())
+	}
+
+	// Sorting and cursor pagination, e.g. "/todos?sort=due_at&order=desc&limit=20&cursor=..."
+	page, err := parseTodoPage(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query, err = page.apply(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	todos, err := query.WithTags().All(ctx)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(todos)
+	var nextCursor string
+	if len(todos) > page.Limit {
+		todos = todos[:page.Limit]
+		nextCursor = page.nextCursor(todos[len(todos)-1])
+		setNextLink(w, r, nextCursor)
+	}
+
+	responses := newTodoResponses(todos, time.Now())
+	if tree {
+		if responses, err = todoTree(ctx, handler.Client, todos, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"todos":       responses,
+		"next_cursor": nextCursor,
+	})
+}
+
+func (handler *Handler) GetTodo(w http.ResponseWriter, r *http.Request) {
+	todoItem, ok := handler.userTodo(w, r)
+	if !ok {
+		return
+	}
+
+	json.NewEncoder(w).Encode(newTodoResponse(todoItem, time.Now()))
+}
+
+func (handler *Handler) UpdateTodo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	todoItem, ok := handler.userTodo(w, r)
+	if !ok {
+		return
+	}
+
+	// Only the fields present in the request body are updated
+	var todoDetails struct {
+		Title        *string             `json:"title"`
+		Status       *string             `json:"status"`
+		Priority     *todo.Priority      `json:"priority"`
+		StartAt      nullable[time.Time] `json:"start_at"`
+		DueAt        nullable[time.Time] `json:"due_at"`
+		ListID       nullable[int]       `json:"list_id"`
+		ParentID     nullable[int]       `json:"parent_id"`
+		AutoComplete *bool               `json:"auto_complete"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&todoDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if todoDetails.Priority != nil {
+		if err := todo.PriorityValidator(*todoDetails.Priority); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if todoDetails.ListID.Set && todoDetails.ListID.Value != nil && !handler.checkListOwner(w, r, *todoDetails.ListID.Value) {
+		return
+	}
+	if todoDetails.ParentID.Set && todoDetails.ParentID.Value != nil && !handler.checkParentTodo(w, r, todoItem.ID, *todoDetails.ParentID.Value) {
+		return
+	}
+
+	// Validate the date range against the values the Todo will have after the update
+	startAt, dueAt := todoItem.StartAt, todoItem.DueAt
+	if todoDetails.StartAt.Set {
+		startAt = todoDetails.StartAt.Value
+	}
+	if todoDetails.DueAt.Set {
+		dueAt = todoDetails.DueAt.Value
+	}
+	if startAt != nil && dueAt != nil && dueAt.Before(*startAt) {
+		http.Error(w, "due_at must not be before start_at", http.StatusBadRequest)
+		return
+	}
+
+	var updatedTodo *ent.Todo
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		update := tx.Todo.UpdateOneID(todoItem.ID).
+			SetNillableTitle(todoDetails.Title).
+			SetNillablePriority(todoDetails.Priority).
+			SetNillableAutoComplete(todoDetails.AutoComplete)
+		if todoDetails.StartAt.Set {
+			if startAt == nil {
+				update.ClearStartAt()
+			} else {
+				update.SetStartAt(*startAt)
+			}
+		}
+		if todoDetails.DueAt.Set {
+			if dueAt == nil {
+				update.ClearDueAt()
+			} else {
+				update.SetDueAt(*dueAt)
+			}
+		}
+		if todoDetails.ListID.Set {
+			// Moving a Todo between lists, or out of any list when list_id is null
+			if todoDetails.ListID.Value == nil {
+				update.ClearListID()
+			} else {
+				update.SetListID(*todoDetails.ListID.Value)
+			}
+		}
+		if todoDetails.ParentID.Set {
+			// Nesting a Todo under another one, or making it top-level when parent_id is null
+			if todoDetails.ParentID.Value == nil {
+				update.ClearParentID()
+			} else {
+				update.SetParentID(*todoDetails.ParentID.Value)
+			}
+		}
+
+		var err error
+		if updatedTodo, err = update.Save(ctx); err != nil {
+			return err
+		}
+
+		// Status changes follow the same workflow rules as POST /todos/{id}/transition
+		if todoDetails.Status != nil {
+			if err := handler.transitionTodo(ctx, tx, updatedTodo, *todoDetails.Status, false); err != nil {
+				return err
+			}
+			updatedTodo, err = tx.Todo.Get(ctx, todoItem.ID)
+		}
+		return err
+	})
+	if !writeTransitionError(w, err) {
+		return
+	}
+
+	json.NewEncoder(w).Encode(newTodoResponse(updatedTodo, time.Now()))
+}
+
+// UpdateTodoSeries edits a Todo together with all open occurrences of its recurring
+// series. Setting recurrence starts a series, and a null recurrence ends it.
+func (handler *Handler) UpdateTodoSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	todoItem, ok := handler.userTodo(w, r)
+	if !ok {
+		return
+	}
+	userID := ctx.Value(userIDKey).(int)
+
+	var seriesDetails struct {
+		Title        *string          `json:"title"`
+		Priority     *todo.Priority   `json:"priority"`
+		AutoComplete *bool            `json:"auto_complete"`
+		Recurrence   nullable[string] `json:"recurrence"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&seriesDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if seriesDetails.Priority != nil {
+		if err := todo.PriorityValidator(*seriesDetails.Priority); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// A new rule is anchored at the date this occurrence was scheduled for
+	var recurrence string
+	occurrenceAt := todoItem.OccurrenceAt
+	if seriesDetails.Recurrence.Value != nil {
+		if occurrenceAt == nil {
+			occurrenceAt = todoItem.DueAt
+		}
+		if occurrenceAt == nil {
+			http.Error(w, "A recurring todo needs a due_at", http.StatusBadRequest)
+			return
+		}
+		var err error
+		if recurrence, err = parseRecurrence(*seriesDetails.Recurrence.Value, *occurrenceAt); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// The series is this Todo plus every other open occurrence sharing its series ID
+	scope := todo.ID(todoItem.ID)
+	if todoItem.SeriesID != nil {
+		scope = todo.Or(scope, todo.And(
+			todo.SeriesID(*todoItem.SeriesID),
+			todo.CompletedAtIsNil(),
+		))
+	}
+
+	var updatedTodo *ent.Todo
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		update := tx.Todo.Update().
+			Where(scope, todo.HasUserWith(user.ID(userID))).
+			SetNillableTitle(seriesDetails.Title).
+			SetNillablePriority(seriesDetails.Priority).
+			SetNillableAutoComplete(seriesDetails.AutoComplete)
+		if seriesDetails.Recurrence.Set {
+			if seriesDetails.Recurrence.Value == nil {
+				update.ClearRecurrence()
+			} else {
+				update.SetRecurrence(recurrence)
+			}
+		}
+		if err := update.Exec(ctx); err != nil {
+			return err
+		}
+		if seriesDetails.Recurrence.Value != nil && todoItem.OccurrenceAt == nil {
+			if err := tx.Todo.UpdateOneID(todoItem.ID).SetOccurrenceAt(*occurrenceAt).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		var err error
+		updatedTodo, err = tx.Todo.Get(ctx, todoItem.ID)
+		return err
+	})
+	if ent.IsConstraintError(err) {
+		http.Error(w, "A todo with this title already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newTodoResponse(updatedTodo, time.Now()))
 }
 
 func (handler *Handler) MarkTodoComplete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	todoItem, ok := handler.userTodo(w, r)
+	if !ok {
+		return
+	}
+
+	// Move the Todo to the workflow's done state. A Todo with open subtasks is only
+	// completed when "?cascade=true" asks for its subtasks to be completed too.
+	cascade := r.URL.Query().Get("cascade") == "true"
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		return handler.transitionTodo(ctx, tx, todoItem, handler.workflow().Done, cascade)
+	})
+	if !writeTransitionError(w, err) {
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Todo marked as complete"})
+}
+
+func (handler *Handler) ReopenTodo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	todoItem, ok := handler.userTodo(w, r)
+	if !ok {
+		return
+	}
+
+	// Move the Todo back to the workflow's initial state
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		return handler.transitionTodo(ctx, tx, todoItem, handler.workflow().Initial, false)
+	})
+	if !writeTransitionError(w, err) {
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Todo reopened"})
+}
+
+// MoveTodo places a todo directly after or before another of the user's todos in
+// their manual ordering, e.g. {"after_id": 3} or {"before_id": 7}.
+func (handler *Handler) MoveTodo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	todoItem, ok := handler.userTodo(w, r)
+	if !ok {
+		return
+	}
+	userID := ctx.Value(userIDKey).(int)
 
-	// Extract the Todo ID from the URL path parameters.
-	// Assuming you're using chi router and the URL pattern is "/todos/{id}/complete"
+	var moveDetails struct {
+		AfterID  *int `json:"after_id"`
+		BeforeID *int `json:"before_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&moveDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if (moveDetails.AfterID == nil) == (moveDetails.BeforeID == nil) {
+		http.Error(w, "Exactly one of after_id and before_id is required", http.StatusBadRequest)
+		return
+	}
+	anchorID, after := moveDetails.BeforeID, false
+	if moveDetails.AfterID != nil {
+		anchorID, after = moveDetails.AfterID, true
+	}
+	if *anchorID == todoItem.ID {
+		http.Error(w, "A todo cannot be moved relative to itself", http.StatusBadRequest)
+		return
+	}
+
+	var movedTodo *ent.Todo
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		anchor, err := tx.Todo.Query().
+			Where(todo.ID(*anchorID), todo.HasUserWith(user.ID(userID))).
+			Only(ctx)
+		if err != nil {
+			return err
+		}
+		position, err := movePosition(ctx, tx, userID, todoItem, anchor, after)
+		if err != nil {
+			return err
+		}
+		movedTodo, err = tx.Todo.UpdateOneID(todoItem.ID).SetPosition(position).Save(ctx)
+		return err
+	})
+	if ent.IsNotFound(err) {
+		http.Error(w, "Anchor todo not found or does not belong to user", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newTodoResponse(movedTodo, time.Now()))
+}
+
+func (handler *Handler) DeleteTodo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	todoItem, ok := handler.userTodo(w, r)
+	if !ok {
+		return
+	}
+
+	if err := handler.Client.Todo.DeleteOne(todoItem).Exec(ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Todo deleted"})
+}
+
+// userTodo loads the Todo named by the {id} URL parameter and ensures it belongs
+// to the user making the request. On failure it writes the error response and returns false.
+func (handler *Handler) userTodo(w http.ResponseWriter, r *http.Request) (*ent.Todo, bool) {
+	ctx := r.Context()
+
+	// Extract the Todo ID from the URL path parameters, e.g. "/todos/{id}/complete"
 	todoID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "Invalid todo ID", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	// Get the userID from the context to ensure the Todo belongs to the user making the request
 	userID, ok := ctx.Value(userIDKey).(int)
 	if !ok {
 		http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
-		return
+		return nil, false
 	}
 
 	// Find the Todo by ID and ensure it belongs to the user
@@ -86,16 +586,8 @@ func (handler *Handler) MarkTodoComplete(w http.ResponseWriter, r *http.Request)
 		Only(ctx)
 	if err != nil {
 		http.Error(w, "Todo not found or does not belong to user", http.StatusNotFound)
-		return
-	}
-
-	// Update the Todo's status to "complete"
-	_, err = todoItem.Update().SetStatus(todo.StatusComplete).Save(ctx)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return nil, false
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Todo marked as complete"})
+	return todoItem, true
 }
@@ -0,0 +1,138 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"todo/viewer"
+)
+
+// TestTodoLifecycle tests creating, reading, updating, completing, reopening and
+// deleting a todo, and that none of it works on another user's todos
+func TestTodoLifecycle(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	ann := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	bob := handler.Client.User.Create().SetName("bob").SetEmail("bob@example.com").SetPassword("hash").SaveX(ctx)
+	bobTodo := handler.Client.Todo.Create().SetTitle("Bob's").SetUser(bob).SaveX(ctx)
+
+	r := newUserRouter(handler, ann.ID)
+	r.Post("/todos", handler.CreateTodo)
+	r.Get("/todos/{id}", handler.GetTodo)
+	r.Patch("/todos/{id}", handler.UpdateTodo)
+	r.Delete("/todos/{id}", handler.DeleteTodo)
+	r.Post("/todos/{id}/complete", handler.MarkTodoComplete)
+	r.Post("/todos/{id}/reopen", handler.ReopenTodo)
+
+	rec := serve(r, http.MethodPost, "/todos", `{"title":"Buy milk"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create: status = %d: %s", rec.Code, rec.Body)
+	}
+	created := decode[TodoResponse](t, rec)
+	path := "/todos/" + strconv.Itoa(created.ID)
+	if created.Title != "Buy milk" || created.Status != handler.workflow().Initial {
+		t.Errorf("created = %+v", created)
+	}
+
+	rec = serve(r, http.MethodPatch, path, `{"title":"Buy oat milk"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: status = %d: %s", rec.Code, rec.Body)
+	}
+	if updated := decode[TodoResponse](t, rec); updated.Title != "Buy oat milk" || updated.Status != created.Status {
+		t.Errorf("partial update changed more than the title: %+v", updated)
+	}
+
+	if rec = serve(r, http.MethodPost, path+"/complete", ""); rec.Code != http.StatusOK {
+		t.Fatalf("complete: status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := decode[TodoResponse](t, serve(r, http.MethodGet, path, "")); got.Status != handler.workflow().Done || got.CompletedAt == nil {
+		t.Errorf("after complete = %+v", got)
+	}
+	if rec = serve(r, http.MethodPost, path+"/reopen", ""); rec.Code != http.StatusOK {
+		t.Fatalf("reopen: status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := decode[TodoResponse](t, serve(r, http.MethodGet, path, "")); got.Status != handler.workflow().Initial || got.CompletedAt != nil {
+		t.Errorf("after reopen = %+v", got)
+	}
+
+	if rec = serve(r, http.MethodDelete, path, ""); rec.Code != http.StatusOK {
+		t.Fatalf("delete: status = %d: %s", rec.Code, rec.Body)
+	}
+	if rec = serve(r, http.MethodGet, path, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("get deleted: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	other := "/todos/" + strconv.Itoa(bobTodo.ID)
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, other, ""},
+		{http.MethodPatch, other, `{"title":"Mine now"}`},
+		{http.MethodPost, other + "/complete", ""},
+		{http.MethodPost, other + "/reopen", ""},
+		{http.MethodDelete, other, ""},
+	}
+	for _, tt := range tests {
+		if rec := serve(r, tt.method, tt.path, tt.body); rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s of another user's todo: status = %d, want %d", tt.method, tt.path, rec.Code, http.StatusNotFound)
+		}
+	}
+	if got := handler.Client.Todo.GetX(ctx, bobTodo.ID); got.Title != "Bob's" || got.Status != handler.workflow().Initial {
+		t.Errorf("other user's todo changed: %+v", got)
+	}
+}
+
+// TestTodoTitleUniquePerUser tests that titles only have to be unique among a user's
+// open todos, and that duplicates are refused with 409
+func TestTodoTitleUniquePerUser(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	ann := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	bob := handler.Client.User.Create().SetName("bob").SetEmail("bob@example.com").SetPassword("hash").SaveX(ctx)
+
+	asAnn := newUserRouter(handler, ann.ID)
+	asAnn.Post("/todos", handler.CreateTodo)
+	asAnn.Patch("/todos/{id}", handler.UpdateTodo)
+	asAnn.Post("/todos/{id}/complete", handler.MarkTodoComplete)
+	asAnn.Post("/todos/{id}/reopen", handler.ReopenTodo)
+	asBob := newUserRouter(handler, bob.ID)
+	asBob.Post("/todos", handler.CreateTodo)
+
+	create := func(r http.Handler, title string) (int, TodoResponse) {
+		rec := serve(r, http.MethodPost, "/todos", `{"title":"`+title+`"}`)
+		if rec.Code != http.StatusOK {
+			return rec.Code, TodoResponse{}
+		}
+		return rec.Code, decode[TodoResponse](t, rec)
+	}
+
+	status, milk := create(asAnn, "Buy milk")
+	if status != http.StatusOK {
+		t.Fatalf("first todo: status = %d", status)
+	}
+	if status, _ := create(asBob, "Buy milk"); status != http.StatusOK {
+		t.Errorf("same title for another user: status = %d, want %d", status, http.StatusOK)
+	}
+	if status, _ := create(asAnn, "Buy milk"); status != http.StatusConflict {
+		t.Errorf("duplicate open title: status = %d, want %d", status, http.StatusConflict)
+	}
+
+	_, bread := create(asAnn, "Buy bread")
+	if rec := serve(asAnn, http.MethodPatch, "/todos/"+strconv.Itoa(bread.ID), `{"title":"Buy milk"}`); rec.Code != http.StatusConflict {
+		t.Errorf("renaming to a duplicate title: status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	// Completed todos don't count, but reopening one brings it back into the index
+	if rec := serve(asAnn, http.MethodPost, "/todos/"+strconv.Itoa(milk.ID)+"/complete", ""); rec.Code != http.StatusOK {
+		t.Fatalf("complete: status = %d: %s", rec.Code, rec.Body)
+	}
+	if status, _ := create(asAnn, "Buy milk"); status != http.StatusOK {
+		t.Errorf("title of a completed todo: status = %d, want %d", status, http.StatusOK)
+	}
+	if rec := serve(asAnn, http.MethodPost, "/todos/"+strconv.Itoa(milk.ID)+"/reopen", ""); rec.Code != http.StatusConflict {
+		t.Errorf("reopening over an open duplicate: status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
@@ -0,0 +1,286 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/predicate"
+	"todo/ent/refreshtoken"
+	"todo/ent/revokedtoken"
+	"todo/ent/session"
+
+	"github.com/go-chi/jwtauth/v5"
+)
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+	// Name of the cookie holding the refresh token, next to the "jwt" access token cookie
+	refreshCookieName = "refresh_token"
+	// How often a session's last_seen_at is written while it is being used
+	lastSeenInterval = time.Minute
+)
+
+const sessionIDKey contextKey = "sessionID"
+
+var errExpiredRefreshToken = errors.New("refresh token expired")
+
+// startSession records a new login of the user from the device making the request
+// and mints its first pair of tokens.
+func (handler *Handler) startSession(ctx context.Context, r *http.Request, user *ent.User) (accessToken, refreshToken string, err error) {
+	var tokenErr error
+	err = withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		newSession, err := tx.Session.Create().
+			SetUser(user).
+			SetUserAgent(r.UserAgent()).
+			SetIP(clientIP(r)).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		accessToken, refreshToken, tokenErr = handler.issueTokens(ctx, tx.Client(), user, newSession.ID)
+		return tokenErr
+	})
+	return accessToken, refreshToken, err
+}
+
+// issueTokens mints a short-lived access token and a refresh token for the user's session.
+func (handler *Handler) issueTokens(ctx context.Context, client *ent.Client, user *ent.User, sessionID int) (accessToken, refreshToken string, err error) {
+	if refreshToken, err = auth.GenerateToken(); err != nil {
+		return "", "", err
+	}
+	err = client.RefreshToken.Create().
+		SetTokenHash(auth.HashToken(refreshToken)).
+		SetExpiresAt(time.Now().Add(refreshTokenTTL)).
+		SetUser(user).
+		SetSessionID(sessionID).
+		Exec(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	if accessToken, err = handler.MakeToken(*user, sessionID); err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+func setAuthCookies(w http.ResponseWriter, accessToken, refreshToken string) {
+	http.SetCookie(w, &http.Cookie{
+		HttpOnly: true,
+		Expires:  time.Now().Add(accessTokenTTL),
+		SameSite: http.SameSiteLaxMode,
+		// Uncomment below for HTTPS:
+		// Secure: true,
+		// Without a path the cookie would only be sent under the directory of the route
+		// that set it, such as /token after a refresh
+		Path:  "/",
+		Name:  "jwt", // Must be named "jwt" or else the token cannot be searched for by jwtauth.Verifier.
+		Value: accessToken,
+	})
+	http.SetCookie(w, &http.Cookie{
+		HttpOnly: true,
+		Expires:  time.Now().Add(refreshTokenTTL),
+		SameSite: http.SameSiteLaxMode,
+		// Uncomment below for HTTPS:
+		// Secure: true,
+		Path:  "/",
+		Name:  refreshCookieName,
+		Value: refreshToken,
+	})
+}
+
+func clearAuthCookies(w http.ResponseWriter) {
+	for _, name := range []string{"jwt", refreshCookieName} {
+		http.SetCookie(w, &http.Cookie{
+			HttpOnly: true,
+			MaxAge:   -1, // Delete the cookie.
+			SameSite: http.SameSiteLaxMode,
+			// Uncomment below for HTTPS:
+			// Secure: true,
+			Path:  "/",
+			Name:  name,
+			Value: "",
+		})
+	}
+}
+
+// Refresh exchanges the refresh token cookie for a new access token and a new refresh
+// token. Each refresh token can only be used once; presenting one that was already
+// rotated revokes the whole session it belongs to.
+func (handler *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cookie, err := r.Cookie(refreshCookieName)
+	if err != nil {
+		http.Error(w, "missing refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	var accessToken, refreshToken string
+	reused := false
+	err = withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		current, err := tx.RefreshToken.Query().
+			Where(refreshtoken.TokenHash(auth.HashToken(cookie.Value))).
+			WithUser().
+			WithSession().
+			Only(ctx)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if current.RevokedAt != nil || current.Edges.Session.RevokedAt != nil {
+			reused = true
+			return revokeSessions(ctx, tx.Client(), session.ID(current.Edges.Session.ID))
+		}
+		if current.ExpiresAt.Before(now) {
+			return errExpiredRefreshToken
+		}
+		if current.Edges.User.DisabledAt != nil {
+			return errAccountDisabled
+		}
+		// Only revoked while still live, so of two refreshes racing with the same token
+		// one rotates it and the other counts as reuse
+		n, err := tx.RefreshToken.Update().
+			Where(refreshtoken.ID(current.ID), refreshtoken.RevokedAtIsNil()).
+			SetRevokedAt(now).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		if n != 1 {
+			reused = true
+			return revokeSessions(ctx, tx.Client(), session.ID(current.Edges.Session.ID))
+		}
+		if err := current.Edges.Session.Update().SetLastSeenAt(now).Exec(ctx); err != nil {
+			return err
+		}
+		accessToken, refreshToken, err = handler.issueTokens(ctx, tx.Client(), current.Edges.User, current.Edges.Session.ID)
+		return err
+	})
+	if ent.IsNotFound(err) || errors.Is(err, errExpiredRefreshToken) || errors.Is(err, errAccountDisabled) || reused {
+		clearAuthCookies(w)
+		http.Error(w, "invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to refresh token", http.StatusInternalServerError)
+		return
+	}
+
+	setAuthCookies(w, accessToken, refreshToken)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Token refreshed successfully",
+	})
+}
+
+// revokeTokens ends the session of the request and denylists its access token, if
+// present, until the access token would have expired anyway.
+func (handler *Handler) revokeTokens(ctx context.Context, r *http.Request) error {
+	if cookie, err := r.Cookie(refreshCookieName); err == nil {
+		sessionID, err := handler.Client.RefreshToken.Query().
+			Where(refreshtoken.TokenHash(auth.HashToken(cookie.Value))).
+			QuerySession().
+			OnlyID(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if err := revokeSessions(ctx, handler.Client, session.ID(sessionID)); err != nil {
+				return err
+			}
+		}
+	}
+
+	token, err := handler.Keys.VerifyRequest(r)
+	if err != nil || token.JwtID() == "" {
+		// Nothing to revoke, the access token is missing, invalid or already expired
+		return nil
+	}
+
+	// Opportunistically drop denylist entries that have outlived their tokens
+	if _, err := handler.Client.RevokedToken.Delete().Where(revokedtoken.ExpiresAtLT(time.Now())).Exec(ctx); err != nil {
+		return err
+	}
+	err = handler.Client.RevokedToken.Create().
+		SetJti(token.JwtID()).
+		SetExpiresAt(token.Expiration()).
+		Exec(ctx)
+	if ent.IsConstraintError(err) {
+		// Already revoked
+		return nil
+	}
+	return err
+}
+
+// revokeSessions ends the matching sessions along with all of their refresh tokens.
+// Access tokens of a revoked session are rejected by RejectRevokedTokens.
+func revokeSessions(ctx context.Context, client *ent.Client, where ...predicate.Session) error {
+	now := time.Now()
+	err := client.Session.Update().
+		Where(where...).
+		Where(session.RevokedAtIsNil()).
+		SetRevokedAt(now).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return client.RefreshToken.Update().
+		Where(refreshtoken.HasSessionWith(where...), refreshtoken.RevokedAtIsNil()).
+		SetRevokedAt(now).
+		Exec(ctx)
+}
+
+// RejectRevokedTokens runs after jwtauth.Authenticator and rejects access tokens that
+// were revoked at logout or belong to a revoked session, as well as legacy tokens
+// minted without a jti and session. It also keeps the session's last_seen_at current.
+func (handler *Handler) RejectRevokedTokens(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		token, claims, err := jwtauth.FromContext(ctx)
+		sessionID, ok := claims[string(sessionIDKey)].(float64)
+		if err != nil || token == nil || token.JwtID() == "" || !ok {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		revoked, err := handler.Client.RevokedToken.Query().
+			Where(revokedtoken.Jti(token.JwtID())).
+			Exist(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		current, err := handler.Client.Session.Get(ctx, int(sessionID))
+		if err != nil && !ent.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if revoked || current == nil || current.RevokedAt != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		if now := time.Now(); now.Sub(current.LastSeenAt) > lastSeenInterval {
+			if err := current.Update().SetLastSeenAt(now).Exec(ctx); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		ctx = context.WithValue(ctx, sessionIDKey, current.ID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// JWKS publishes the public keys access tokens are signed with, so other services
+// can verify them.
+func (handler *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(handler.Keys.JWKS())
+}
@@ -0,0 +1,242 @@
+package routes
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/refreshtoken"
+	"todo/viewer"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// TestMakeToken tests that access tokens expire and can be told apart by their jti
+func TestMakeToken(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwk.FromRaw(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := auth.NewKeySet(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &Handler{Keys: keys}
+	user := ent.User{ID: 7, Name: "alice", Email: "alice@example.com"}
+
+	first, err := handler.MakeToken(user, 3)
+	if err != nil {
+		t.Fatalf("MakeToken error: %v", err)
+	}
+	second, err := handler.MakeToken(user, 3)
+	if err != nil {
+		t.Fatalf("MakeToken error: %v", err)
+	}
+
+	token, err := handler.Keys.Verify(first, auth.AccessTokenType)
+	if err != nil {
+		t.Fatalf("VerifyToken error: %v", err)
+	}
+	if token.JwtID() == "" {
+		t.Error("token has no jti")
+	}
+	if ttl := time.Until(token.Expiration()); ttl <= 0 || ttl > accessTokenTTL {
+		t.Errorf("token expires in %v, want within %v", ttl, accessTokenTTL)
+	}
+	if userID, _ := token.Get("userID"); userID != float64(user.ID) {
+		t.Errorf("userID claim = %v, want %d", userID, user.ID)
+	}
+	if sessionID, _ := token.Get("sessionID"); sessionID != float64(3) {
+		t.Errorf("sessionID claim = %v, want 3", sessionID)
+	}
+
+	other, err := handler.Keys.Verify(second, auth.AccessTokenType)
+	if err != nil {
+		t.Fatalf("VerifyToken error: %v", err)
+	}
+	if token.JwtID() == other.JwtID() {
+		t.Error("two tokens share the same jti")
+	}
+}
+
+// TestRefreshCookies tests that the access token cookie set by a refresh is the one
+// sent to protected routes outside /token
+func TestRefreshCookies(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	hash, err := handler.passwords().Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword(hash).SaveX(ctx)
+
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(SystemViewer)
+		r.Post("/login", handler.Login)
+		r.Post("/token/refresh", handler.Refresh)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(handler.Keys.Verifier(), handler.Keys.Authenticator(), handler.RejectRevokedTokens, UserContextMiddleware, handler.ViewerMiddleware)
+		r.Get("/me", handler.GetMe)
+	})
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Jar: jar}
+
+	resp, err := client.Post(server.URL+"/login", "application/json", strings.NewReader(`{"email":"ann@example.com","password":"correct horse"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	resp, err = client.Post(server.URL+"/token/refresh", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("refresh status = %d", resp.StatusCode)
+	}
+	var refreshed string
+	for _, c := range resp.Cookies() {
+		if c.Name == "jwt" {
+			refreshed = c.Value
+		}
+	}
+
+	me, _ := url.Parse(server.URL + "/me")
+	var sent []string
+	for _, c := range jar.Cookies(me) {
+		if c.Name == "jwt" {
+			sent = append(sent, c.Value)
+		}
+	}
+	if len(sent) != 1 || sent[0] != refreshed {
+		t.Errorf("/me is sent %d jwt cookies, want only the refreshed one", len(sent))
+	}
+
+	resp, err = client.Get(me.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/me status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+// TestTokenTypes tests that tokens minted for another purpose aren't accepted as
+// access tokens
+func TestTokenTypes(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	s := handler.Client.Session.Create().SetUser(u).SaveX(ctx)
+
+	access, err := handler.MakeToken(*u, s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mfa, err := handler.makeMFAToken(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Carries every claim an access token has, but was minted for a download link
+	claims := map[string]interface{}{"userID": u.ID, "sessionID": s.ID, "jti": "x", exportIDKey: 1}
+	jwtauth.SetExpiryIn(claims, time.Minute)
+	_, export, err := handler.Keys.Encode(exportTokenType, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"access token", access, http.StatusOK},
+		{"mfa token", mfa, http.StatusUnauthorized},
+		{"export token", export, http.StatusUnauthorized},
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	protected := handler.AcceptAccessTokens(chi.Chain(
+		handler.Keys.Verifier(),
+		handler.Keys.Authenticator(),
+		handler.RejectRevokedTokens,
+		UserContextMiddleware,
+	).Handler)(ok)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			protected.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+// TestConcurrentRefresh tests that of several refreshes racing with the same refresh
+// token at most one gets new tokens, so no second token chain is started
+func TestConcurrentRefresh(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	u := handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	_, refreshToken, err := handler.startSession(ctx, httptest.NewRequest(http.MethodPost, "/login", nil), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const attempts = 8
+	statuses := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/token/refresh", nil).WithContext(ctx)
+			req.AddCookie(&http.Cookie{Name: refreshCookieName, Value: refreshToken})
+			rec := httptest.NewRecorder()
+			handler.Refresh(rec, req)
+			statuses <- rec.Code
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	succeeded := 0
+	for status := range statuses {
+		if status == http.StatusOK {
+			succeeded++
+		}
+	}
+	if succeeded > 1 {
+		t.Errorf("%d refreshes succeeded with the same token, want at most 1", succeeded)
+	}
+	if live := handler.Client.RefreshToken.Query().Where(refreshtoken.RevokedAtIsNil()).CountX(ctx); live > 1 {
+		t.Errorf("%d live refresh tokens, want at most 1", live)
+	}
+}
@@ -0,0 +1,166 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"todo/ent"
+	"todo/ent/todo"
+	"todo/workflow"
+)
+
+var (
+	errOpenSubtasks      = errors.New("todo has open subtasks")
+	errIllegalTransition = errors.New("transition not allowed")
+)
+
+// TransitionTodo moves a todo to another workflow state, e.g. {"to": "in_progress"}.
+// Entering a terminal state with open subtasks is rejected unless "cascade" is set.
+func (handler *Handler) TransitionTodo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	todoItem, ok := handler.userTodo(w, r)
+	if !ok {
+		return
+	}
+
+	var transitionDetails struct {
+		To      string `json:"to"`
+		Cascade bool   `json:"cascade"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&transitionDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var updatedTodo *ent.Todo
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		if err := handler.transitionTodo(ctx, tx, todoItem, transitionDetails.To, transitionDetails.Cascade); err != nil {
+			return err
+		}
+		var err error
+		updatedTodo, err = tx.Todo.Get(ctx, todoItem.ID)
+		return err
+	})
+	if !writeTransitionError(w, err) {
+		return
+	}
+
+	json.NewEncoder(w).Encode(newTodoResponse(updatedTodo, time.Now()))
+}
+
+// GetWorkflow describes the configured workflow states and transitions to clients.
+func (handler *Handler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(handler.workflow())
+}
+
+// workflow returns the configured workflow, falling back to workflow.Default.
+func (handler *Handler) workflow() *workflow.Workflow {
+	if handler.Workflow == nil {
+		return workflow.Default
+	}
+	return handler.Workflow
+}
+
+// transitionTodo moves t to the given workflow state. Entering a terminal state records
+// completed_at, closes the subtasks when cascade is set, completes ancestors with
+// auto_complete set once all of their subtasks are closed, and schedules the next
+// occurrence of a recurring Todo. Leaving a terminal state clears completed_at.
+func (handler *Handler) transitionTodo(ctx context.Context, tx *ent.Tx, t *ent.Todo, to string, cascade bool) error {
+	wf := handler.workflow()
+	closed, err := handler.moveTodo(ctx, tx, t, to, cascade)
+	if err != nil || !closed {
+		return err
+	}
+
+	// Roll completion up to the parent if it asked for it
+	if t.ParentID == nil {
+		return nil
+	}
+	parent, err := tx.Todo.Get(ctx, *t.ParentID)
+	if err != nil {
+		return err
+	}
+	if !parent.AutoComplete || parent.CompletedAt != nil || !wf.CanTransition(parent.Status, wf.Done) {
+		return nil
+	}
+	open, err := tx.Todo.Query().
+		Where(todo.ParentID(parent.ID), todo.CompletedAtIsNil()).
+		Exist(ctx)
+	if err != nil || open {
+		return err
+	}
+	return handler.transitionTodo(ctx, tx, parent, wf.Done, false)
+}
+
+// moveTodo does the work of transitionTodo for t and, when cascading, its subtrees,
+// without rolling completion up to the parent. It reports whether t was closed.
+func (handler *Handler) moveTodo(ctx context.Context, tx *ent.Tx, t *ent.Todo, to string, cascade bool) (bool, error) {
+	wf := handler.workflow()
+	if !wf.HasState(to) || !wf.CanTransition(t.Status, to) {
+		return false, fmt.Errorf("%w: %q to %q", errIllegalTransition, t.Status, to)
+	}
+	if t.Status == to {
+		return false, nil
+	}
+
+	update := tx.Todo.UpdateOneID(t.ID).SetStatus(to)
+	closing := wf.IsTerminal(to) && t.CompletedAt == nil
+	if !wf.IsTerminal(to) {
+		update.ClearCompletedAt()
+	}
+	if !closing {
+		return false, update.Exec(ctx)
+	}
+
+	open, err := tx.Todo.Query().
+		Where(todo.ParentID(t.ID), todo.CompletedAtIsNil()).
+		Order(todo.ByID()).
+		All(ctx)
+	if err != nil {
+		return false, err
+	}
+	if len(open) > 0 && !cascade {
+		return false, errOpenSubtasks
+	}
+	// Each subtask goes through the workflow like any other Todo, so the cascade is
+	// refused if one of them can't enter the state
+	for _, child := range open {
+		if !wf.CanTransition(child.Status, to) {
+			return false, fmt.Errorf("%w: subtask %q can't move from %q to %q", errOpenSubtasks, child.Title, child.Status, to)
+		}
+		if _, err := handler.moveTodo(ctx, tx, child, to, true); err != nil {
+			return false, err
+		}
+	}
+
+	if err := update.SetCompletedAt(time.Now()).Exec(ctx); err != nil {
+		return false, err
+	}
+
+	// Completing an occurrence of a recurring Todo schedules the next one
+	return true, handler.scheduleNextOccurrence(ctx, tx, t)
+}
+
+// writeTransitionError writes the response for an error returned by transitionTodo.
+// It returns true if there was no error.
+func writeTransitionError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errIllegalTransition):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, errOpenSubtasks) && err != errOpenSubtasks:
+		// A subtask stopped the cascade
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, errOpenSubtasks):
+		http.Error(w, "Todo has open subtasks, close them first or cascade", http.StatusConflict)
+	case ent.IsConstraintError(err):
+		http.Error(w, "An open todo with this title already exists", http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return false
+}
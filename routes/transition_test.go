@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo/ent"
+	"todo/ent/todo"
+	"todo/viewer"
+)
+
+// TestCascadeTransition tests that closing a todo with cascade runs every open subtask
+// through the workflow
+func TestCascadeTransition(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	client := handler.Client
+	u := client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(ctx)
+	due := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		childStatus string
+		wantErr     error
+	}{
+		{"recurring subtask", "in_progress", nil},
+		{"blocked subtask", "blocked", errOpenSubtasks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := client.Todo.Create().SetTitle("Release " + tt.name).SetUser(u).SaveX(ctx)
+			child := client.Todo.Create().SetTitle("Standup " + tt.name).SetUser(u).SetParent(parent).
+				SetStatus(tt.childStatus).SetRecurrence("FREQ=DAILY").SetDueAt(due).SaveX(ctx)
+
+			err := withTx(ctx, client, func(tx *ent.Tx) error {
+				return handler.transitionTodo(ctx, tx, parent, "done", true)
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("transitionTodo error = %v, want %v", err, tt.wantErr)
+			}
+
+			closed := client.Todo.GetX(ctx, child.ID).CompletedAt != nil
+			next := client.Todo.Query().Where(todo.SeriesID(child.ID), todo.IDNEQ(child.ID)).CountX(ctx)
+			if tt.wantErr != nil && (closed || next != 0 || client.Todo.GetX(ctx, parent.ID).CompletedAt != nil) {
+				t.Error("refused cascade changed todos")
+			}
+			if tt.wantErr == nil && (!closed || next != 1) {
+				t.Errorf("subtask closed = %v with %d next occurrences, want closed with 1", closed, next)
+			}
+		})
+	}
+}
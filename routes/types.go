@@ -1,13 +1,166 @@
 package routes
 
 import (
+	"encoding/json"
+	"time"
+	auth "todo/auth"
 	"todo/ent"
-
-	"github.com/go-chi/jwtauth/v5"
+	"todo/ent/todo"
+	"todo/mailer"
+	"todo/workflow"
 )
 
 type Handler struct {
-	Client    *ent.Client
-	TokenAuth *jwtauth.JWTAuth
-	User      *ent.User
+	Client *ent.Client
+	// Keys signs and verifies the JWTs the server issues
+	Keys     *auth.KeySet
+	User     *ent.User
+	Workflow *workflow.Workflow
+	Mailer   mailer.Mailer
+	// AppURL is the base URL of the client app, used for links sent by email
+	AppURL string
+	// EmailVerification decides which routes RequireVerifiedEmail blocks for unverified users
+	EmailVerification VerificationPolicy
+	// OIDC is set when logging in through an OpenID Connect provider is enabled
+	OIDC *OIDC
+	// Passwords hashes passwords, auth.DefaultPasswordHasher when nil
+	Passwords *auth.PasswordHasher
+	// PasswordPolicy decides which passwords users may choose, auth.DefaultPasswordPolicy when nil
+	PasswordPolicy *auth.PasswordPolicy
+	// Roles maps role names to the permissions they grant, auth.DefaultRoles when nil
+	Roles auth.Roles
+}
+
+// Handlers never encode ent entities directly. Eager-loaded edges would otherwise
+// pull in other entities, such as the owning User, with every field they have.
+
+// PublicUser is what any logged in user can see about another user.
+type PublicUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func newPublicUser(u *ent.User) PublicUser {
+	return PublicUser{ID: u.ID, Name: u.Name}
+}
+
+// PrivateUser is the JSON representation of the logged in user's own account.
+type PrivateUser struct {
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	Age              int    `json:"age,omitempty"`
+	EmailVerified    bool   `json:"email_verified"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
+}
+
+func newPrivateUser(u *ent.User) PrivateUser {
+	return PrivateUser{
+		ID:               u.ID,
+		Name:             u.Name,
+		Email:            u.Email,
+		Age:              u.Age,
+		EmailVerified:    u.EmailVerified,
+		TwoFactorEnabled: u.TotpEnabled,
+	}
+}
+
+// ListResponse is the JSON representation of a List.
+type ListResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newListResponse(l *ent.List) ListResponse {
+	return ListResponse{ID: l.ID, Name: l.Name, CreatedAt: l.CreatedAt}
+}
+
+// TagResponse is the JSON representation of a Tag.
+type TagResponse struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+func newTagResponse(t *ent.Tag) TagResponse {
+	return TagResponse{ID: t.ID, Name: t.Name, Color: t.Color}
+}
+
+// TodoResponse is the JSON representation of a Todo, including computed fields.
+type TodoResponse struct {
+	ID           int           `json:"id"`
+	Title        string        `json:"title"`
+	Status       string        `json:"status"`
+	Priority     todo.Priority `json:"priority"`
+	Position     int64         `json:"position"`
+	StartAt      *time.Time    `json:"start_at,omitempty"`
+	DueAt        *time.Time    `json:"due_at,omitempty"`
+	CompletedAt  *time.Time    `json:"completed_at,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	ListID       *int          `json:"list_id,omitempty"`
+	ParentID     *int          `json:"parent_id,omitempty"`
+	AutoComplete bool          `json:"auto_complete"`
+	Recurrence   *string       `json:"recurrence,omitempty"`
+	SeriesID     *int          `json:"series_id,omitempty"`
+	OccurrenceAt *time.Time    `json:"occurrence_at,omitempty"`
+	Overdue      bool          `json:"overdue"`
+	// Tags is only populated when they were loaded with the todo
+	Tags []TagResponse `json:"tags,omitempty"`
+	// Children is only populated when the todo tree is requested
+	Children []TodoResponse `json:"children,omitempty"`
+}
+
+func newTodoResponse(t *ent.Todo, now time.Time) TodoResponse {
+	response := TodoResponse{
+		ID:           t.ID,
+		Title:        t.Title,
+		Status:       t.Status,
+		Priority:     t.Priority,
+		Position:     t.Position,
+		StartAt:      t.StartAt,
+		DueAt:        t.DueAt,
+		CompletedAt:  t.CompletedAt,
+		CreatedAt:    t.CreatedAt,
+		ListID:       t.ListID,
+		ParentID:     t.ParentID,
+		AutoComplete: t.AutoComplete,
+		Recurrence:   t.Recurrence,
+		SeriesID:     t.SeriesID,
+		OccurrenceAt: t.OccurrenceAt,
+		Overdue:      t.DueAt != nil && t.DueAt.Before(now) && t.CompletedAt == nil,
+	}
+	for _, tag := range t.Edges.Tags {
+		response.Tags = append(response.Tags, newTagResponse(tag))
+	}
+	return response
+}
+
+func newTodoResponses(todos []*ent.Todo, now time.Time) []TodoResponse {
+	responses := make([]TodoResponse, len(todos))
+	for i, t := range todos {
+		responses[i] = newTodoResponse(t, now)
+	}
+	return responses
+}
+
+// nullable distinguishes a value that was omitted from a request body from one
+// that was explicitly set to null, so PATCH requests can clear optional fields.
+type nullable[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (n *nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.Value = &v
+	return nil
 }
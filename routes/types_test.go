@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"todo/ent"
+)
+
+// TestTodoResponseOverdue tests the computed overdue flag
+func TestTodoResponseOverdue(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		todo *ent.Todo
+		want bool
+	}{
+		{"no due date", &ent.Todo{Status: "todo"}, false},
+		{"due in the future", &ent.Todo{Status: "todo", DueAt: &future}, false},
+		{"due in the past", &ent.Todo{Status: "in_progress", DueAt: &past}, true},
+		{"completed after due date", &ent.Todo{Status: "done", DueAt: &past, CompletedAt: &now}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTodoResponse(tt.todo, now).Overdue; got != tt.want {
+				t.Errorf("newTodoResponse().Overdue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNullable tests that omitted, null and set values are told apart
+func TestNullable(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantSet   bool
+		wantValue bool
+	}{
+		{"omitted", `{}`, false, false},
+		{"null", `{"due_at": null}`, true, false},
+		{"set", `{"due_at": "2024-03-01T12:00:00Z"}`, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body struct {
+				DueAt nullable[time.Time] `json:"due_at"`
+			}
+			if err := json.Unmarshal([]byte(tt.body), &body); err != nil {
+				t.Fatalf("Unmarshal(%s) error: %v", tt.body, err)
+			}
+			if body.DueAt.Set != tt.wantSet || (body.DueAt.Value != nil) != tt.wantValue {
+				t.Errorf("Unmarshal(%s) = %+v, want Set=%v, Value set=%v", tt.body, body.DueAt, tt.wantSet, tt.wantValue)
+			}
+		})
+	}
+}
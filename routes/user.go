@@ -3,6 +3,7 @@ package routes
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,18 +11,31 @@ import (
 	auth "todo/auth"
 	"todo/ent"
 	"todo/ent/user"
+	"todo/mailer"
+	"todo/viewer"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type contextKey string
 
 const userIDKey contextKey = "userID"
 
-func (handler *Handler) MakeToken(user ent.User) (string, error) {
-	_, tokenString, err := handler.TokenAuth.Encode(map[string]interface{}{"username": user.Name, "email": user.Email, "userID": user.ID})
+var errAccountDisabled = errors.New("account disabled")
+
+// MakeToken mints a short-lived access token for one of the user's sessions. It carries
+// a unique jti, so it can be revoked at logout. Its role claim is informational only;
+// permissions are checked against the role stored in the database.
+func (handler *Handler) MakeToken(user ent.User, sessionID int) (string, error) {
+	jti, err := auth.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	claims := map[string]interface{}{"username": user.Name, "email": user.Email, "userID": user.ID, "role": user.Role, "sessionID": sessionID, "jti": jti}
+	jwtauth.SetIssuedNow(claims)
+	jwtauth.SetExpiryIn(claims, accessTokenTTL)
+	_, tokenString, err := handler.Keys.Encode(auth.AccessTokenType, claims)
 	if err != nil {
 		log.Println("Failed to create token: ", err)
 	}
@@ -46,8 +60,44 @@ func UserContextMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// ViewerMiddleware makes the authenticated user the viewer that the ent privacy rules
+// scope queries to. It runs after UserContextMiddleware or AcceptAccessTokens. The role
+// is read from the database rather than the token's claims, so demoting or disabling a
+// user takes effect on their next request.
+func (handler *Handler) ViewerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userID, ok := ctx.Value(userIDKey).(int)
+		if !ok {
+			http.Error(w, "Unauthorized or invalid user ID", http.StatusUnauthorized)
+			return
+		}
+		// There is no viewer until the user is loaded
+		u, err := handler.Client.User.Get(viewer.SystemContext(ctx), userID)
+		if ent.IsNotFound(err) || (err == nil && u.DisabledAt != nil) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ctx = context.WithValue(ctx, roleKey, u.Role)
+		ctx = viewer.NewContext(ctx, viewer.Viewer{UserID: userID, Permissions: handler.roles()[u.Role], Roles: handler.roles()})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// SystemViewer runs the login, registration and account recovery routes as the system
+// viewer. They authenticate the user themselves, so there is no user to act for yet.
+func SystemViewer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(viewer.SystemContext(r.Context())))
+	})
+}
+
 func (handler *Handler) Login(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 
 	var loginDetails struct {
 		Email    string `json:"email"`
@@ -58,34 +108,79 @@ func (handler *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Retrieve the user by name
+	// Unknown emails, locked accounts and wrong passwords all get the same answer, so
+	// the response doesn't tell which emails are registered
 	user, err := handler.Client.User.Query().Where(user.Email(loginDetails.Email)).Only(ctx)
+	if ent.IsNotFound(err) {
+		handler.passwords().CheckDummy(loginDetails.Password)
+		handler.recordLoginAttempt(ctx, r, loginDetails.Email, nil, false)
+		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		return
+	}
 	if err != nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if isLocked(user) {
+		handler.passwords().CheckDummy(loginDetails.Password)
+		handler.recordLoginAttempt(ctx, r, user.Email, user, false)
+		http.Error(w, "invalid credentials", http.StatusUnauthorized)
 		return
 	}
 
 	// Validate password
-	if !auth.CheckPasswordHash(loginDetails.Password, user.Password) {
+	if !handler.passwords().Check(loginDetails.Password, user.Password) {
+		if err := handler.loginFailed(ctx, r, user); err != nil {
+			log.Println("Failed to record failed login: ", err)
+		}
 		http.Error(w, "invalid credentials", http.StatusUnauthorized)
 		return
 	}
 
-	token, err := handler.MakeToken(*user)
+	// Upgrade hashes made with an older algorithm or cost while the password is known
+	if handler.passwords().NeedsRehash(user.Password) {
+		if hash, err := handler.passwords().Hash(loginDetails.Password); err != nil {
+			log.Println("Failed to rehash password: ", err)
+		} else if err := user.Update().SetPassword(hash).Exec(ctx); err != nil {
+			log.Println("Failed to store rehashed password: ", err)
+		}
+	}
+
+	handler.completeLogin(w, r, user)
+}
+
+// completeLogin starts a session for a user whose credentials have been checked, or
+// asks for their second factor first when they have two-factor authentication.
+func (handler *Handler) completeLogin(w http.ResponseWriter, r *http.Request, u *ent.User) {
+	if u.DisabledAt != nil {
+		http.Error(w, errAccountDisabled.Error(), http.StatusForbidden)
+		return
+	}
+
+	// With two-factor authentication the session is only started by LoginMFA
+	if u.TotpEnabled {
+		mfaToken, err := handler.makeMFAToken(u)
+		if err != nil {
+			http.Error(w, "Failed to create token", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"mfa_required": true,
+			"mfa_token":    mfaToken,
+		})
+		return
+	}
+
+	if err := handler.loginSucceeded(r.Context(), r, u); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	accessToken, refreshToken, err := handler.startSession(r.Context(), r, u)
 	if err != nil {
 		http.Error(w, "Failed to create token", http.StatusInternalServerError)
 		return
 	}
-
-	http.SetCookie(w, &http.Cookie{
-		HttpOnly: true,
-		Expires:  time.Now().Add(7 * 24 * time.Hour),
-		SameSite: http.SameSiteLaxMode,
-		// Uncomment below for HTTPS:
-		// Secure: true,
-		Name:  "jwt", // Must be named "jwt" or else the token cannot be searched for by jwtauth.Verifier.
-		Value: token,
-	})
+	setAuthCookies(w, accessToken, refreshToken)
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message": "User logged in successfully",
@@ -93,22 +188,39 @@ func (handler *Handler) Login(w http.ResponseWriter, r *http.Request) {
 }
 
 func (handler *Handler) Logout(w http.ResponseWriter, r *http.Request) {
-	http.SetCookie(w, &http.Cookie{
-		HttpOnly: true,
-		MaxAge:   -1, // Delete the cookie.
-		SameSite: http.SameSiteLaxMode,
-		// Uncomment below for HTTPS:
-		// Secure: true,
-		Name:  "jwt",
-		Value: "",
-	})
+	ctx := r.Context()
+	if err := handler.revokeTokens(ctx, r); err != nil {
+		http.Error(w, "Failed to revoke token", http.StatusInternalServerError)
+		return
+	}
+
+	clearAuthCookies(w)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message": "User logged out successfully",
 	})
 }
 
+// mailTimeout bounds how long sending an email in the background may take.
+const mailTimeout = time.Minute
+
+// sendInBackground sends an email after the response has been written, so how long
+// the request took doesn't reveal whether an account exists. Errors are logged.
+func sendInBackground(what string, send func(ctx context.Context) error) {
+	go func() {
+		ctx, cancel := context.WithTimeout(viewer.SystemContext(context.Background()), mailTimeout)
+		defer cancel()
+		if err := send(ctx); err != nil {
+			log.Printf("Failed to send %s: %v", what, err)
+		}
+	}()
+}
+
+// Register creates an account and emails a verification link to its address. It
+// responds the same way when the address is already registered, emailing its owner
+// instead, so it can't be used to find out who has an account. The user logs in
+// once registered.
 func (handler *Handler) Register(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 	var registrationDetails struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
@@ -121,65 +233,77 @@ func (handler *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Hash the password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(registrationDetails.Password), bcrypt.DefaultCost)
-	if err != nil {
-		http.Error(w, "Failed to hash password", http.StatusInternalServerError)
+	if !validEmail(registrationDetails.Email) {
+		http.Error(w, "invalid email address", http.StatusBadRequest)
+		return
+	}
+
+	// Check the password against the policy and hash it
+	hashedPassword, ok := handler.hashNewPassword(w, registrationDetails.Password)
+	if !ok {
 		return
 	}
 
 	// Create the user
-	user, err := handler.Client.User.Create().
+	u, err := handler.Client.User.Create().
 		SetEmail(registrationDetails.Email).
-		SetPassword(string(hashedPassword)). // Use the hashed password
+		SetPassword(hashedPassword). // Use the hashed password
 		SetName(registrationDetails.Name).
 		SetAge(registrationDetails.Age).
 		Save(ctx)
-
-	if err != nil {
+	switch {
+	case ent.IsConstraintError(err):
+		// Name and age are unique too, so the address may still be free
+		registered, err := handler.Client.User.Query().Where(user.Email(registrationDetails.Email)).Exist(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !registered {
+			http.Error(w, "name and age already taken", http.StatusConflict)
+			return
+		}
+		email := registrationDetails.Email
+		sendInBackground("registration notice", func(ctx context.Context) error {
+			return handler.sendAlreadyRegistered(ctx, email)
+		})
+	case err != nil:
 		http.Error(w, fmt.Sprintf("Error creating user: %v", err), http.StatusInternalServerError)
 		return
+	default:
+		// The user can ask for another one with POST /verify/resend
+		sendInBackground("verification email", func(ctx context.Context) error {
+			return handler.sendVerificationEmail(ctx, u)
+		})
 	}
 
-	token, err := handler.MakeToken(*user)
-	if err != nil {
-		http.Error(w, "Failed to create token", http.StatusInternalServerError)
-		return
-	}
-
-	http.SetCookie(w, &http.Cookie{
-		HttpOnly: true,
-		Expires:  time.Now().Add(7 * 24 * time.Hour),
-		SameSite: http.SameSiteLaxMode,
-		// Uncomment below for HTTPS:
-		// Secure: true,
-		Name:  "jwt", // Must be named "jwt" or else the token cannot be searched for by jwtauth.Verifier.
-		Value: token,
-	})
-
+	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "User registered successfully",
+		"message": "Check your email to finish registering",
 	})
 }
 
-func (handler *Handler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	users, err := handler.Client.User.Query().All(ctx)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("error fetching users: %v", err), http.StatusInternalServerError)
-		return
-	}
-	json.NewEncoder(w).Encode(users)
+// sendAlreadyRegistered tells the owner of an address that someone tried to register
+// it again.
+func (handler *Handler) sendAlreadyRegistered(ctx context.Context, email string) error {
+	return handler.Mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "You already have an account",
+		Body: fmt.Sprintf("Hi,\n\nSomeone tried to register with this email address, which already has an account.\n\n"+
+			"If it was you, log in at %s instead, or reset your password there if you forgot it.\n\n"+
+			"If it wasn't you, you can ignore this email.\n", handler.AppURL),
+	})
 }
 
+// QueryUser looks up the public profile of any user by name.
 func (handler *Handler) QueryUser(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	// Deliberately not limited to the viewer's own account, only public fields are returned
+	ctx := viewer.SystemContext(r.Context())
 	name := chi.URLParam(r, "name")
 	user, err := handler.Client.User.Query().Where(user.Name(name)).Only(ctx)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("no user or multiple users found: %v", err), http.StatusInternalServerError)
 		return
 	}
-	log.Println("user returned: ", user)
-	json.NewEncoder(w).Encode(user)
+	json.NewEncoder(w).Encode(newPublicUser(user))
 }
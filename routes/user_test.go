@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todo/mailer"
+	"todo/viewer"
+)
+
+// chanMailer hands sent emails to the test, since they are sent in the background
+type chanMailer chan mailer.Message
+
+func (m chanMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m <- msg
+	return nil
+}
+
+// TestRegisterDoesNotRevealAccounts tests that registering a taken address responds
+// like registering a new one, and emails the owner of the address instead
+func TestRegisterDoesNotRevealAccounts(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	sent := make(chanMailer, 1)
+	handler.Mailer = sent
+	handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetAge(30).SetPassword("hash").SaveX(ctx)
+
+	// Names and ages differ from every other user, so only the address can collide
+	register := func(email, name string, age int) (*httptest.ResponseRecorder, mailer.Message) {
+		body := fmt.Sprintf(`{"email":%q,"password":"correct horse battery","name":%q,"age":%d}`, email, name, age)
+		req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body)).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		handler.Register(rec, req)
+		select {
+		case msg := <-sent:
+			return rec, msg
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no email sent registering %s, status %d: %s", email, rec.Code, rec.Body)
+			return nil, mailer.Message{}
+		}
+	}
+	fresh, verification := register("bob@example.com", "bob", 31)
+	taken, notice := register("ann@example.com", "cat", 32)
+
+	if fresh.Code != http.StatusAccepted || taken.Code != fresh.Code {
+		t.Errorf("status = %d for a new address and %d for a taken one, want %d", fresh.Code, taken.Code, http.StatusAccepted)
+	}
+	if taken.Body.String() != fresh.Body.String() {
+		t.Errorf("body = %q for a taken address, want %q", taken.Body, fresh.Body)
+	}
+	if len(fresh.Result().Cookies()) != 0 || len(taken.Result().Cookies()) != 0 {
+		t.Error("registering set cookies")
+	}
+	if verification.To != "bob@example.com" || !strings.Contains(verification.Body, "/verify?token=") {
+		t.Errorf("new address got %+v, want a verification email", verification)
+	}
+	if notice.To != "ann@example.com" || strings.Contains(notice.Body, "token=") {
+		t.Errorf("taken address got %+v, want a notice without links carrying tokens", notice)
+	}
+}
+
+// TestRegisterNameAndAgeTaken tests that a new address with the name and age of an
+// existing user is refused, rather than treated as an address that is registered
+func TestRegisterNameAndAgeTaken(t *testing.T) {
+	ctx := viewer.SystemContext(context.Background())
+	handler := newTestHandler(t)
+	sent := make(chanMailer, 1)
+	handler.Mailer = sent
+	handler.Client.User.Create().SetName("ann").SetEmail("ann@example.com").SetAge(30).SetPassword("hash").SaveX(ctx)
+
+	body := `{"email":"bob@example.com","password":"correct horse battery","name":"ann","age":30}`
+	req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	handler.Register(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	select {
+	case msg := <-sent:
+		t.Errorf("sent %+v, want no email", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
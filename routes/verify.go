@@ -0,0 +1,172 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"time"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/emailverificationtoken"
+	"todo/ent/user"
+	"todo/mailer"
+)
+
+const emailVerificationTTL = 48 * time.Hour
+
+var errVerificationEmailChanged = errors.New("email changed since the verification was sent")
+
+// VerificationPolicy decides which protected routes require a verified email address.
+type VerificationPolicy string
+
+const (
+	// VerifyOff lets unverified users use every route
+	VerifyOff VerificationPolicy = "off"
+	// VerifyWrites lets unverified users read but not create, change or delete anything
+	VerifyWrites VerificationPolicy = "writes"
+	// VerifyAll blocks unverified users from every route the policy is applied to
+	VerifyAll VerificationPolicy = "all"
+)
+
+// ParseVerificationPolicy reads a policy from its configured name. An empty name means VerifyOff.
+func ParseVerificationPolicy(name string) (VerificationPolicy, error) {
+	switch policy := VerificationPolicy(name); policy {
+	case "":
+		return VerifyOff, nil
+	case VerifyOff, VerifyWrites, VerifyAll:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown email verification policy %q", name)
+	}
+}
+
+// validEmail reports whether email is a bare address such as "ann@example.com".
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
+
+// sendVerificationEmail emails the user a link confirming they own their current address.
+func (handler *Handler) sendVerificationEmail(ctx context.Context, u *ent.User) error {
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return err
+	}
+	err = handler.Client.EmailVerificationToken.Create().
+		SetTokenHash(auth.HashToken(token)).
+		SetEmail(u.Email).
+		SetExpiresAt(time.Now().Add(emailVerificationTTL)).
+		SetUser(u).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify?token=%s", handler.AppURL, url.QueryEscape(token))
+	return handler.Mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %v.\n\n%s\n",
+			u.Name, emailVerificationTTL, link),
+	})
+}
+
+// VerifyEmail marks the user's address as verified using a token from a verification email.
+func (handler *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token := r.URL.Query().Get("token")
+
+	err := withTx(ctx, handler.Client, func(tx *ent.Tx) error {
+		verification, err := tx.EmailVerificationToken.Query().
+			Where(
+				emailverificationtoken.TokenHash(auth.HashToken(token)),
+				emailverificationtoken.UsedAtIsNil(),
+				emailverificationtoken.ExpiresAtGT(time.Now()),
+			).
+			WithUser().
+			Only(ctx)
+		if err != nil {
+			return err
+		}
+		// A link sent to a previous address must not verify the current one
+		if verification.Email != verification.Edges.User.Email {
+			return errVerificationEmailChanged
+		}
+		if err := verification.Update().SetUsedAt(time.Now()).Exec(ctx); err != nil {
+			return err
+		}
+		return verification.Edges.User.Update().SetEmailVerified(true).Exec(ctx)
+	})
+	if ent.IsNotFound(err) || errors.Is(err, errVerificationEmailChanged) {
+		http.Error(w, "invalid or expired verification token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to verify email", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendVerification sends a new verification email to the current user.
+func (handler *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(userIDKey).(int)
+
+	u, err := handler.Client.User.Get(ctx, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if u.EmailVerified {
+		http.Error(w, "email already verified", http.StatusConflict)
+		return
+	}
+	if err := handler.sendVerificationEmail(ctx, u); err != nil {
+		http.Error(w, "Failed to send verification email", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Verification email sent",
+	})
+}
+
+// RequireVerifiedEmail rejects requests from users who haven't verified their email
+// address, according to the handler's EmailVerification policy.
+func (handler *Handler) RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch handler.EmailVerification {
+		case "", VerifyOff:
+			next.ServeHTTP(w, r)
+			return
+		case VerifyWrites:
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		ctx := r.Context()
+		userID := ctx.Value(userIDKey).(int)
+		verified, err := handler.Client.User.Query().
+			Where(user.ID(userID), user.EmailVerified(true)).
+			Exist(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !verified {
+			http.Error(w, "email address not verified", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
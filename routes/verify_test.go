@@ -0,0 +1,49 @@
+package routes
+
+import "testing"
+
+// TestParseVerificationPolicy tests reading the configured email verification policy
+func TestParseVerificationPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    VerificationPolicy
+		wantErr bool
+	}{
+		{"", VerifyOff, false},
+		{"off", VerifyOff, false},
+		{"writes", VerifyWrites, false},
+		{"all", VerifyAll, false},
+		{"sometimes", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVerificationPolicy(tt.name)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseVerificationPolicy(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidEmail tests that only bare addresses are accepted at registration
+func TestValidEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{"ann@example.com", true},
+		{"", false},
+		{"ann", false},
+		{"Ann <ann@example.com>", false},
+		{" ann@example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if got := validEmail(tt.email); got != tt.want {
+				t.Errorf("validEmail(%q) = %v, want %v", tt.email, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package rule holds the ent privacy rules and query interceptors that scope Todo and
+// User access to the viewer in the context, see package viewer.
+package rule
+
+import (
+	"context"
+	"slices"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/intercept"
+	"todo/ent/privacy"
+	"todo/ent/todo"
+	"todo/ent/user"
+	"todo/viewer"
+)
+
+// DenyIfNoViewer denies queries and mutations made without a viewer in the context.
+func DenyIfNoViewer() privacy.QueryMutationRule {
+	return privacy.ContextQueryMutationRule(func(ctx context.Context) error {
+		if _, ok := viewer.FromContext(ctx); !ok {
+			return privacy.Denyf("viewer is missing from the context")
+		}
+		return privacy.Skip
+	})
+}
+
+// AllowIfSystem allows everything done as the system viewer.
+func AllowIfSystem() privacy.QueryMutationRule {
+	return privacy.ContextQueryMutationRule(func(ctx context.Context) error {
+		if v, _ := viewer.FromContext(ctx); v.IsSystem() {
+			return privacy.Allow
+		}
+		return privacy.Skip
+	})
+}
+
+// FilterTodos limits Todo queries, including traversals from other entities, to the
+// viewer's own todos. Viewers who may read everyone's only see them where the context
+// asks for them, see viewer.WithAllTodos.
+func FilterTodos() ent.Interceptor {
+	return intercept.TraverseTodo(func(ctx context.Context, q *ent.TodoQuery) error {
+		v, ok := viewer.FromContext(ctx)
+		if !ok {
+			// Denied by the privacy policy
+			return nil
+		}
+		if v.IsSystem() || (v.Can(auth.PermReadAllTodos) && viewer.WantsAllTodos(ctx)) {
+			return nil
+		}
+		q.Where(todo.HasUserWith(user.ID(v.UserID)))
+		return nil
+	})
+}
+
+// FilterUsers limits User queries to the viewer's own account unless the viewer may
+// list or manage users.
+func FilterUsers() ent.Interceptor {
+	return intercept.TraverseUser(func(ctx context.Context, q *ent.UserQuery) error {
+		v, ok := viewer.FromContext(ctx)
+		if !ok {
+			return nil
+		}
+		if !v.Can(auth.PermListUsers) && !v.Can(auth.PermManageUsers) {
+			q.Where(user.ID(v.UserID))
+		}
+		return nil
+	})
+}
+
+// TodoOwnedByViewer limits Todo mutations to the viewer's own todos. New todos must
+// belong to the viewer and existing ones can't be handed to another user.
+func TodoOwnedByViewer() privacy.MutationRule {
+	return privacy.TodoMutationRuleFunc(func(ctx context.Context, m *ent.TodoMutation) error {
+		v, _ := viewer.FromContext(ctx)
+		if ownerID, ok := m.UserID(); ok && ownerID != v.UserID {
+			return privacy.Denyf("todo can't be given to another user")
+		}
+		if m.Op().Is(ent.OpCreate) {
+			if _, ok := m.UserID(); !ok {
+				return privacy.Denyf("todo must belong to the viewer")
+			}
+			return privacy.Allow
+		}
+		if m.UserCleared() {
+			return privacy.Denyf("todo can't be left without a user")
+		}
+		m.Where(todo.HasUserWith(user.ID(v.UserID)))
+		return privacy.Allow
+	})
+}
+
+// UserIsViewer limits User mutations to the viewer's own account, or any account for
+// viewers who may manage users. Accounts are only created by the system viewer and
+// roles only changed by viewers who may manage roles. No one can change users whose
+// role grants permissions they lack, or hand such a role out.
+func UserIsViewer() privacy.MutationRule {
+	return privacy.UserMutationRuleFunc(func(ctx context.Context, m *ent.UserMutation) error {
+		v, _ := viewer.FromContext(ctx)
+		if m.Op().Is(ent.OpCreate) {
+			return privacy.Denyf("users can only be created by the system")
+		}
+		higher := v.HigherRoles()
+		if role, ok := m.Role(); ok {
+			if !v.Can(auth.PermManageRoles) {
+				return privacy.Denyf("only role managers can change roles")
+			}
+			if slices.Contains(higher, role) {
+				return privacy.Denyf("role %q grants permissions the viewer lacks", role)
+			}
+		}
+		if v.Can(auth.PermManageUsers) {
+			if len(higher) == 0 {
+				return privacy.Allow
+			}
+			ids, err := m.IDs(ctx)
+			if err != nil {
+				return privacy.Denyf("loading the users to change: %v", err)
+			}
+			outranked, err := m.Client().User.Query().
+				Where(user.IDIn(ids...), user.RoleIn(higher...)).
+				Exist(ctx)
+			if err != nil {
+				return privacy.Denyf("loading the users to change: %v", err)
+			}
+			if outranked {
+				return privacy.Denyf("users whose role grants permissions the viewer lacks can't be changed")
+			}
+			return privacy.Allow
+		}
+		if _, ok := m.DisabledAt(); ok || m.DisabledAtCleared() {
+			return privacy.Denyf("only user managers can disable or enable accounts")
+		}
+		m.Where(user.ID(v.UserID))
+		return privacy.Allow
+	})
+}
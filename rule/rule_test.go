@@ -0,0 +1,150 @@
+package rule_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	auth "todo/auth"
+	"todo/ent"
+	"todo/ent/enttest"
+	"todo/ent/privacy"
+	"todo/viewer"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// TestTodoAndUserPolicies tests that queries and mutations are scoped to the viewer in
+// the context
+func TestTodoAndUserPolicies(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", fmt.Sprintf("file:%s?mode=memory&_fk=1", t.Name()))
+	defer client.Close()
+
+	system := viewer.SystemContext(context.Background())
+	ann := client.User.Create().SetName("ann").SetEmail("ann@example.com").SetPassword("hash").SaveX(system)
+	bob := client.User.Create().SetName("bob").SetEmail("bob@example.com").SetPassword("hash").SaveX(system)
+	annTodo := client.Todo.Create().SetTitle("Ship it").SetUser(ann).SaveX(system)
+	bobTodo := client.Todo.Create().SetTitle("Write tests").SetUser(bob).SaveX(system)
+
+	asAnn := viewer.NewContext(context.Background(), viewer.Viewer{UserID: ann.ID})
+	asAdmin := viewer.NewContext(context.Background(), viewer.Viewer{UserID: bob.ID, Permissions: auth.Permissions})
+	cat := client.User.Create().SetName("cat").SetEmail("cat@example.com").SetPassword("hash").SetRole(auth.RoleAdmin).SaveX(system)
+	managers := []auth.Permission{auth.PermListUsers, auth.PermManageUsers, auth.PermManageRoles}
+	asManager := viewer.NewContext(context.Background(), viewer.Viewer{
+		UserID:      ann.ID,
+		Permissions: managers,
+		Roles:       auth.Roles{auth.RoleUser: nil, auth.RoleAdmin: auth.Permissions, "support": managers},
+	})
+
+	tests := []struct {
+		name   string
+		run    func() error
+		denied bool
+	}{
+		{"no viewer can't query todos", func() error {
+			_, err := client.Todo.Query().All(context.Background())
+			return err
+		}, true},
+		{"no viewer can't update users", func() error {
+			return client.User.UpdateOneID(ann.ID).SetName("eve").Exec(context.Background())
+		}, true},
+		{"viewer only sees own todos", func() error {
+			todos, err := client.Todo.Query().All(asAnn)
+			if err == nil && (len(todos) != 1 || todos[0].ID != annTodo.ID) {
+				return fmt.Errorf("got %d todos", len(todos))
+			}
+			return err
+		}, false},
+		{"traversals are scoped too", func() error {
+			n, err := client.User.Query().QueryTodos().Count(asAnn)
+			if err == nil && n != 1 {
+				return fmt.Errorf("got %d todos", n)
+			}
+			return err
+		}, false},
+		{"viewer only sees own account", func() error {
+			n, err := client.User.Query().Count(asAnn)
+			if err == nil && n != 1 {
+				return fmt.Errorf("got %d users", n)
+			}
+			return err
+		}, false},
+		{"viewer can't update others' todos", func() error {
+			err := client.Todo.UpdateOneID(bobTodo.ID).SetTitle("Mine now").Exec(asAnn)
+			if !ent.IsNotFound(err) {
+				return fmt.Errorf("got %v, want not found", err)
+			}
+			return nil
+		}, false},
+		{"viewer can't create todos for others", func() error {
+			return client.Todo.Create().SetTitle("Spam").SetUser(bob).Exec(asAnn)
+		}, true},
+		{"viewer can't give a todo away", func() error {
+			return client.Todo.UpdateOneID(annTodo.ID).SetUser(bob).Exec(asAnn)
+		}, true},
+		{"viewer can't change their role", func() error {
+			return client.User.UpdateOneID(ann.ID).SetRole(auth.RoleAdmin).Exec(asAnn)
+		}, true},
+		{"viewer can't create users", func() error {
+			return client.User.Create().SetName("eve").SetEmail("eve@example.com").SetPassword("hash").Exec(asAnn)
+		}, true},
+		{"viewer can update own account", func() error {
+			return client.User.UpdateOneID(ann.ID).SetName("annie").Exec(asAnn)
+		}, false},
+		{"admin only sees own todos by default", func() error {
+			todos, err := client.Todo.Query().All(asAdmin)
+			if err == nil && (len(todos) != 1 || todos[0].ID != bobTodo.ID) {
+				return fmt.Errorf("got %d todos", len(todos))
+			}
+			return err
+		}, false},
+		{"viewer can't ask for all todos", func() error {
+			n, err := client.Todo.Query().Count(viewer.WithAllTodos(asAnn))
+			if err == nil && n != 1 {
+				return fmt.Errorf("got %d todos", n)
+			}
+			return err
+		}, false},
+		{"admin sees all todos when asking and all users", func() error {
+			todos, err := client.Todo.Query().Count(viewer.WithAllTodos(asAdmin))
+			if err != nil {
+				return err
+			}
+			users, err := client.User.Query().Count(asAdmin)
+			if err == nil && (todos != 2 || users != 3) {
+				return fmt.Errorf("got %d todos and %d users", todos, users)
+			}
+			return err
+		}, false},
+		{"admin can change roles", func() error {
+			return client.User.UpdateOneID(ann.ID).SetRole("support").Exec(asAdmin)
+		}, false},
+		{"user manager can't disable higher roles", func() error {
+			return client.User.UpdateOneID(cat.ID).SetDisabledAt(annTodo.CreatedAt).Exec(asManager)
+		}, true},
+		{"user manager can't update higher roles in bulk", func() error {
+			return client.User.Update().SetPassword("").Exec(asManager)
+		}, true},
+		{"role manager can't hand out higher roles", func() error {
+			return client.User.UpdateOneID(bob.ID).SetRole(auth.RoleAdmin).Exec(asManager)
+		}, true},
+		{"user manager can disable lower roles", func() error {
+			return client.User.UpdateOneID(bob.ID).SetDisabledAt(annTodo.CreatedAt).Exec(asManager)
+		}, false},
+		{"admin can disable accounts", func() error {
+			return client.User.UpdateOneID(ann.ID).SetDisabledAt(annTodo.CreatedAt).Exec(asAdmin)
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if tt.denied && !errors.Is(err, privacy.Deny) {
+				t.Errorf("error = %v, want denied", err)
+			}
+			if !tt.denied && err != nil {
+				t.Errorf("error = %v", err)
+			}
+		})
+	}
+}
@@ -1,20 +1,26 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	auth "todo/auth"
 	"todo/ent"
+	_ "todo/ent/runtime"
+	"todo/mailer"
 	routes "todo/routes"
+	"todo/workflow"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/go-chi/httprate"
-	"github.com/go-chi/jwtauth/v5"
 	_ "github.com/lib/pq"
 )
 
@@ -22,11 +28,41 @@ var (
 	PG_USER     = os.Getenv("PG_USER")
 	PG_PASSWORD = os.Getenv("PG_PASSWORD")
 	PG_DB       = os.Getenv("PG_DB")
-	JWT_SECRET  = os.Getenv("JWT_SECRET")
+	// PEM private key (RSA or Ed25519) that access tokens are signed with
+	JWT_SIGNING_KEY_FILE = os.Getenv("JWT_SIGNING_KEY_FILE")
+	// Comma separated PEM keys that tokens are still accepted from, such as the previous signing key
+	JWT_VERIFICATION_KEY_FILES = os.Getenv("JWT_VERIFICATION_KEY_FILES")
+	// Optional JSON file describing the todo workflow, see workflow.Workflow
+	WORKFLOW_FILE = os.Getenv("WORKFLOW_FILE")
+	// Base URL of the client app, used for links in emails
+	APP_URL = os.Getenv("APP_URL")
+	// Set to development to run without SMTP, see SMTP_ADDR
+	APP_ENV = os.Getenv("APP_ENV")
+	// Emails are sent through SMTP_ADDR. Only in development can it be left unset, and
+	// emails are then written to MAIL_LOG_FILE or stdout
+	SMTP_ADDR     = os.Getenv("SMTP_ADDR")
+	SMTP_USERNAME = os.Getenv("SMTP_USERNAME")
+	SMTP_PASSWORD = os.Getenv("SMTP_PASSWORD")
+	MAIL_FROM     = os.Getenv("MAIL_FROM")
+	MAIL_LOG_FILE = os.Getenv("MAIL_LOG_FILE")
+	// Which routes unverified users are blocked from: off (default), writes or all
+	EMAIL_VERIFICATION = os.Getenv("EMAIL_VERIFICATION")
+	// Logging in through an OpenID Connect provider is enabled when OIDC_ISSUER is set
+	OIDC_ISSUER        = os.Getenv("OIDC_ISSUER")
+	OIDC_CLIENT_ID     = os.Getenv("OIDC_CLIENT_ID")
+	OIDC_CLIENT_SECRET = os.Getenv("OIDC_CLIENT_SECRET")
+	OIDC_REDIRECT_URL  = os.Getenv("OIDC_REDIRECT_URL")
+	// Password hashing: bcrypt (default) or argon2id, and the bcrypt cost
+	PASSWORD_HASH        = os.Getenv("PASSWORD_HASH")
+	PASSWORD_BCRYPT_COST = os.Getenv("PASSWORD_BCRYPT_COST")
+	// Password policy, see auth.PasswordPolicy
+	PASSWORD_MIN_LENGTH = os.Getenv("PASSWORD_MIN_LENGTH")
+	PASSWORD_MAX_LENGTH = os.Getenv("PASSWORD_MAX_LENGTH")
+	PWNED_PASSWORDS_DIR = os.Getenv("PWNED_PASSWORDS_DIR")
+	// Custom roles next to the built-in user and admin roles, see auth.ParseRoles
+	ROLES = os.Getenv("ROLES")
 )
 
-var tokenAuth *jwtauth.JWTAuth
-
 func main() {
 	connectionString := fmt.Sprintf("host=localhost port=5432 user=%s dbname=%s password=%s sslmode=disable", PG_USER, PG_DB, PG_PASSWORD)
 	client, err := ent.Open("postgres", connectionString)
@@ -46,43 +82,242 @@ func main() {
 	// Basic CORS
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://foo.com", "http://localhost:3000"}, // client origins
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true, // allow for cookies (jwt in our case)
 		MaxAge:           300,  // Preflight request cache duration
 	}))
 
+	todoWorkflow := workflow.Default
+	if WORKFLOW_FILE != "" {
+		todoWorkflow, err = workflow.Load(WORKFLOW_FILE)
+		if err != nil {
+			log.Fatalf("failed loading workflow: %v", err)
+		}
+	}
+
+	// Emails carry password reset, unlock and verification links, which must not end up in logs
+	if SMTP_ADDR == "" {
+		if APP_ENV != "development" {
+			log.Fatal("SMTP_ADDR is not set; set APP_ENV=development to write emails to MAIL_LOG_FILE or stdout instead")
+		}
+		log.Println("WARNING: SMTP_ADDR is not set, emails and the login links in them are written to MAIL_LOG_FILE or stdout")
+	}
+	var mail mailer.Mailer = mailer.NewLogMailer(os.Stdout)
+	switch {
+	case SMTP_ADDR != "":
+		mail = mailer.NewSMTPMailer(SMTP_ADDR, SMTP_USERNAME, SMTP_PASSWORD, MAIL_FROM)
+	case MAIL_LOG_FILE != "":
+		mailLog, err := os.OpenFile(MAIL_LOG_FILE, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			log.Fatalf("failed opening mail log: %v", err)
+		}
+		defer mailLog.Close()
+		mail = mailer.NewLogMailer(mailLog)
+	}
+
+	verificationPolicy, err := routes.ParseVerificationPolicy(EMAIL_VERIFICATION)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	passwords, passwordPolicy, err := passwordConfig()
+	if err != nil {
+		log.Fatalf("invalid password configuration: %v", err)
+	}
+
+	roles, err := auth.ParseRoles(ROLES)
+	if err != nil {
+		log.Fatalf("invalid roles: %v", err)
+	}
+
 	// auth & handler
-	tokenAuth = jwtauth.New("HS256", []byte(JWT_SECRET), nil)
-	handler := &routes.Handler{Client: client, TokenAuth: tokenAuth}
+	var verificationKeyFiles []string
+	if JWT_VERIFICATION_KEY_FILES != "" {
+		verificationKeyFiles = strings.Split(JWT_VERIFICATION_KEY_FILES, ",")
+	}
+	keys, err := auth.LoadKeySet(JWT_SIGNING_KEY_FILE, verificationKeyFiles...)
+	if err != nil {
+		log.Fatalf("failed loading JWT keys: %v", err)
+	}
+	handler := &routes.Handler{
+		Client:   client,
+		Keys:     keys,
+		Workflow: todoWorkflow,
+		Mailer:   mail,
+		AppURL:   APP_URL,
+
+		EmailVerification: verificationPolicy,
+		Passwords:         passwords,
+		PasswordPolicy:    passwordPolicy,
+		Roles:             roles,
+	}
+	if OIDC_ISSUER != "" {
+		handler.OIDC, err = routes.NewOIDC(context.Background(), OIDC_ISSUER, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET, OIDC_REDIRECT_URL)
+		if err != nil {
+			log.Fatalf("failed setting up OIDC login: %v", err)
+		}
+	}
+
+	r.Get("/.well-known/jwks.json", handler.JWKS)
 
 	// Public routes
 	r.Group(func(r chi.Router) {
 		//  prevent brute force attacks
 		r.Use(httprate.LimitByIP(10, 1*time.Minute))
+		r.Use(routes.SystemViewer)
 		r.Post("/login", handler.Login)
+		r.Post("/login/mfa", handler.LoginMFA)
 		r.Post("/logout", handler.Logout)
 		r.Post("/register", handler.Register)
+		r.Post("/token/refresh", handler.Refresh)
+		r.Post("/password/forgot", handler.ForgotPassword)
+		r.Post("/password/reset", handler.ResetPassword)
+		r.Get("/verify", handler.VerifyEmail)
+		r.Get("/unlock", handler.UnlockAccount)
+		// Authorized by the signed token in the link
+		r.Get("/exports/{id}/download", handler.DownloadExport)
+		if handler.OIDC != nil {
+			r.Get("/oidc/login", handler.OIDCLogin)
+			r.Get("/oidc/callback", handler.OIDCCallback)
+		}
 	})
 
 	// Protected routes
 	r.Group(func(r chi.Router) {
-		// Seek, verify and validate JWT tokens
-		r.Use(jwtauth.Verifier(tokenAuth))
-		r.Use(jwtauth.Authenticator(tokenAuth))
-		r.Use(routes.UserContextMiddleware)
-		r.Get("/users/{name}", handler.QueryUser)
-		r.Get("/users", handler.GetAllUsers)
-		r.Post("/todos", handler.CreateTodo)
-		r.Get("/todos", handler.GetTodos)
-		r.Post("/todos/{id}/complete", handler.MarkTodoComplete)
+		// Personal access tokens, or else seek, verify and validate JWT tokens
+		r.Use(handler.AcceptAccessTokens(chi.Chain(
+			keys.Verifier(),
+			keys.Authenticator(),
+			handler.RejectRevokedTokens,
+			routes.UserContextMiddleware,
+		).Handler))
+		// Todo and User queries are scoped to the authenticated user from here on
+		r.Use(handler.ViewerMiddleware)
+
+		// Account management isn't open to personal access tokens
+		r.Group(func(r chi.Router) {
+			r.Use(routes.RequireSession)
+			r.Get("/me", handler.GetMe)
+			r.Patch("/me", handler.UpdateMe)
+			r.Delete("/me", handler.DeleteMe)
+			r.Post("/me/password", handler.ChangePassword)
+			r.Post("/me/export", handler.CreateExport)
+			r.Get("/me/exports", handler.GetExports)
+			r.Get("/me/exports/{id}", handler.GetExport)
+			r.Get("/sessions", handler.GetSessions)
+			r.Delete("/sessions", handler.DeleteSessions)
+			r.Delete("/sessions/{id}", handler.DeleteSession)
+			r.Post("/verify/resend", handler.ResendVerification)
+			r.Post("/2fa/enroll", handler.EnrollTOTP)
+			r.Post("/2fa/confirm", handler.ConfirmTOTP)
+			r.Post("/2fa/disable", handler.DisableTOTP)
+			r.Post("/2fa/recovery-codes", handler.RegenerateRecoveryCodes)
+			r.Post("/tokens", handler.CreateAccessToken)
+			r.Get("/tokens", handler.GetAccessTokens)
+			r.Delete("/tokens/{id}", handler.DeleteAccessToken)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(routes.RequireScope("users"))
+			r.Get("/users/{name}", handler.QueryUser)
+		})
+
+		// Administration, by permission of the user's role
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(routes.RequireSession)
+			r.With(handler.RequirePermission(auth.PermListUsers)).Get("/users", handler.GetAllUsers)
+			r.Group(func(r chi.Router) {
+				r.Use(handler.RequirePermission(auth.PermManageUsers))
+				r.Post("/users/{id}/disable", handler.DisableUser)
+				r.Post("/users/{id}/enable", handler.EnableUser)
+				r.Post("/users/{id}/unlock", handler.UnlockUser)
+				r.Post("/users/{id}/password-reset", handler.ForcePasswordReset)
+			})
+			r.With(handler.RequirePermission(auth.PermReadAllTodos)).Get("/users/{id}/todos", handler.GetUserTodos)
+			r.With(handler.RequirePermission(auth.PermManageRoles)).Put("/users/{id}/role", handler.SetUserRole)
+		})
+
+		// Routes unverified users can be kept out of, see EMAIL_VERIFICATION
+		r.Group(func(r chi.Router) {
+			r.Use(handler.RequireVerifiedEmail)
+
+			r.Group(func(r chi.Router) {
+				r.Use(routes.RequireScope("todos"))
+				r.Post("/todos", handler.CreateTodo)
+				r.Get("/todos", handler.GetTodos)
+				r.Get("/todos/{id}", handler.GetTodo)
+				r.Patch("/todos/{id}", handler.UpdateTodo)
+				r.Delete("/todos/{id}", handler.DeleteTodo)
+				r.Patch("/todos/{id}/series", handler.UpdateTodoSeries)
+				r.Post("/todos/{id}/complete", handler.MarkTodoComplete)
+				r.Post("/todos/{id}/reopen", handler.ReopenTodo)
+				r.Post("/todos/{id}/move", handler.MoveTodo)
+				r.Post("/todos/{id}/transition", handler.TransitionTodo)
+				r.Put("/todos/{id}/tags/{tagID}", handler.AttachTag)
+				r.Delete("/todos/{id}/tags/{tagID}", handler.DetachTag)
+				r.Get("/workflow", handler.GetWorkflow)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(routes.RequireScope("lists"))
+				r.Post("/lists", handler.CreateList)
+				r.Get("/lists", handler.GetLists)
+				r.Get("/lists/{id}", handler.GetList)
+				r.Patch("/lists/{id}", handler.UpdateList)
+				r.Delete("/lists/{id}", handler.DeleteList)
+				r.Get("/lists/{id}/todos", handler.GetListTodos)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(routes.RequireScope("tags"))
+				r.Post("/tags", handler.CreateTag)
+				r.Get("/tags", handler.GetTags)
+				r.Patch("/tags/{id}", handler.UpdateTag)
+				r.Delete("/tags/{id}", handler.DeleteTag)
+			})
+		})
 	})
 
 	log.Println("Starting server on :8080")
 	http.ListenAndServe(":8080", r)
 }
 
+// passwordConfig builds the password hasher and policy from the environment.
+func passwordConfig() (*auth.PasswordHasher, *auth.PasswordPolicy, error) {
+	algorithm := auth.Bcrypt
+	if PASSWORD_HASH != "" {
+		algorithm = PASSWORD_HASH
+	}
+	passwords, err := auth.NewPasswordHasher(algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+	policy := auth.DefaultPasswordPolicy
+	policy.BreachedDir = PWNED_PASSWORDS_DIR
+
+	for _, setting := range []struct {
+		value string
+		dest  *int
+	}{
+		{PASSWORD_BCRYPT_COST, &passwords.BcryptCost},
+		{PASSWORD_MIN_LENGTH, &policy.MinLength},
+		{PASSWORD_MAX_LENGTH, &policy.MaxLength},
+	} {
+		if setting.value == "" {
+			continue
+		}
+		if *setting.dest, err = strconv.Atoi(setting.value); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := passwords.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return passwords, &policy, policy.Validate(passwords)
+}
+
 // test function for github actions
 func Sum(x, y int) int {
 	return x + y
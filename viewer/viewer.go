@@ -0,0 +1,82 @@
+// Package viewer carries who a request or job acts for in its context. The ent
+// privacy rules in package rule scope every Todo and User query and mutation to it.
+package viewer
+
+import (
+	"context"
+	"slices"
+	auth "todo/auth"
+)
+
+// Viewer is the user on whose behalf the database is accessed, or the system itself.
+type Viewer struct {
+	UserID int
+	// Permissions granted by the user's role
+	Permissions []auth.Permission
+	// Roles resolves other users' roles, to compare them with the viewer's
+	Roles  auth.Roles
+	system bool
+}
+
+// Can reports whether the viewer has the permission. The system viewer has them all.
+func (v Viewer) Can(permission auth.Permission) bool {
+	return v.system || slices.Contains(v.Permissions, permission)
+}
+
+// HigherRoles lists the roles granting a permission the viewer lacks. Managing users
+// with one of them, or handing one out, would reach beyond the viewer's own role.
+func (v Viewer) HigherRoles() []string {
+	if v.system {
+		return nil
+	}
+	var higher []string
+	for role, permissions := range v.Roles {
+		for _, p := range permissions {
+			if !v.Can(p) {
+				higher = append(higher, role)
+				break
+			}
+		}
+	}
+	return higher
+}
+
+// IsSystem reports whether the viewer is the system rather than a user.
+func (v Viewer) IsSystem() bool {
+	return v.system
+}
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying the viewer.
+func NewContext(ctx context.Context, v Viewer) context.Context {
+	return context.WithValue(ctx, ctxKey{}, v)
+}
+
+// FromContext returns the viewer carried by ctx, if any.
+func FromContext(ctx context.Context) (Viewer, bool) {
+	v, ok := ctx.Value(ctxKey{}).(Viewer)
+	return v, ok
+}
+
+type allTodosKey struct{}
+
+// WithAllTodos returns a copy of ctx asking for every user's todos rather than only the
+// viewer's. It only has an effect for viewers allowed to read all todos, and is meant
+// for the admin routes that need it.
+func WithAllTodos(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTodosKey{}, true)
+}
+
+// WantsAllTodos reports whether ctx asks for every user's todos, see WithAllTodos.
+func WantsAllTodos(ctx context.Context) bool {
+	all, _ := ctx.Value(allTodosKey{}).(bool)
+	return all
+}
+
+// SystemContext returns a copy of ctx acting as the system, which bypasses all privacy
+// rules. It is meant for background jobs, migrations and the authentication flows that
+// run before there is a user to act for.
+func SystemContext(ctx context.Context) context.Context {
+	return NewContext(ctx, Viewer{system: true})
+}
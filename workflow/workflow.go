@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// Workflow describes the states a Todo can be in and the transitions allowed between them.
+type Workflow struct {
+	// Initial is the state new todos start in and reopened todos return to
+	Initial string `json:"initial"`
+	// Done is the terminal state a todo moves to when it is marked complete
+	Done string `json:"done"`
+	// Terminal states close a todo and record its completed_at time
+	Terminal []string `json:"terminal"`
+	// Transitions maps every state to the states it may move to
+	Transitions map[string][]string `json:"transitions"`
+}
+
+// Default is used when no workflow file is configured.
+var Default = &Workflow{
+	Initial:  "todo",
+	Done:     "done",
+	Terminal: []string{"done", "cancelled"},
+	Transitions: map[string][]string{
+		"todo":        {"in_progress", "blocked", "done", "cancelled"},
+		"in_progress": {"todo", "blocked", "done", "cancelled"},
+		"blocked":     {"todo", "in_progress", "cancelled"},
+		"done":        {"todo"},
+		"cancelled":   {"todo"},
+	},
+}
+
+// Load reads a workflow from a JSON file and validates it.
+func Load(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var wf Workflow
+	if err := json.Unmarshal(data, &wf); err != nil {
+		return nil, fmt.Errorf("parsing workflow %s: %w", path, err)
+	}
+	if err := wf.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid workflow %s: %w", path, err)
+	}
+	return &wf, nil
+}
+
+// Validate checks that every state the workflow refers to is declared in Transitions.
+func (wf *Workflow) Validate() error {
+	if len(wf.Transitions) == 0 {
+		return errors.New("no states defined")
+	}
+	if !wf.HasState(wf.Initial) {
+		return fmt.Errorf("unknown initial state %q", wf.Initial)
+	}
+	if !wf.HasState(wf.Done) || !wf.IsTerminal(wf.Done) {
+		return fmt.Errorf("done state %q must be a known terminal state", wf.Done)
+	}
+	if wf.IsTerminal(wf.Initial) {
+		return fmt.Errorf("initial state %q must not be terminal", wf.Initial)
+	}
+	for _, state := range wf.Terminal {
+		if !wf.HasState(state) {
+			return fmt.Errorf("unknown terminal state %q", state)
+		}
+	}
+	for from, targets := range wf.Transitions {
+		for _, to := range targets {
+			if !wf.HasState(to) {
+				return fmt.Errorf("transition from %q to unknown state %q", from, to)
+			}
+		}
+	}
+	return nil
+}
+
+// HasState reports whether state is part of the workflow.
+func (wf *Workflow) HasState(state string) bool {
+	_, ok := wf.Transitions[state]
+	return ok
+}
+
+// IsTerminal reports whether entering state closes a todo.
+func (wf *Workflow) IsTerminal(state string) bool {
+	return slices.Contains(wf.Terminal, state)
+}
+
+// CanTransition reports whether a todo may move from one state to another.
+// Staying in the same state is always allowed.
+func (wf *Workflow) CanTransition(from, to string) bool {
+	return from == to || slices.Contains(wf.Transitions[from], to)
+}
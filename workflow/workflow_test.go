@@ -0,0 +1,53 @@
+package workflow
+
+import "testing"
+
+// TestDefaultWorkflow tests the transitions allowed by the default workflow
+func TestDefaultWorkflow(t *testing.T) {
+	if err := Default.Validate(); err != nil {
+		t.Fatalf("Default.Validate() error: %v", err)
+	}
+
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"todo", "in_progress", true},
+		{"in_progress", "done", true},
+		{"blocked", "done", false},
+		{"done", "todo", true},
+		{"done", "in_progress", false},
+		{"cancelled", "cancelled", true},
+		{"todo", "archived", false},
+	}
+
+	for _, tt := range tests {
+		if got := Default.CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+// TestValidate tests that inconsistent workflows are rejected
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		wf   Workflow
+	}{
+		{"no states", Workflow{Initial: "todo", Done: "done"}},
+		{"unknown initial", Workflow{Initial: "new", Done: "done", Terminal: []string{"done"},
+			Transitions: map[string][]string{"todo": {"done"}, "done": nil}}},
+		{"done not terminal", Workflow{Initial: "todo", Done: "done",
+			Transitions: map[string][]string{"todo": {"done"}, "done": nil}}},
+		{"unknown target", Workflow{Initial: "todo", Done: "done", Terminal: []string{"done"},
+			Transitions: map[string][]string{"todo": {"done", "archived"}, "done": nil}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.wf.Validate(); err == nil {
+				t.Errorf("Validate() succeeded, want error")
+			}
+		})
+	}
+}